@@ -15,7 +15,9 @@ var buildCmd = &cobra.Command{
 	Use:   "build",
 	Short: "Builds the Vortex application into a Wasm module.",
 	Long: `Compiles the Go source code into a WebAssembly module (app.wasm) and
-copies the necessary wasm_exec.js file. This command should be run from
+copies the necessary wasm_exec.js file. The styles registered in the
+InitializeStyles function of the styles package are extracted to a hashed
+app.css file, linked from index.html. This command should be run from
 the root of a Vortex project.`,
 	Run: runBuild,
 }
@@ -49,6 +51,13 @@ func runBuild(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 	fmt.Println("✅ Copied wasm_exec.js.")
+
+	// Extract the registered styles, the runtime will not inject them again
+	if fileName, err := extractStaticCSS(); err != nil {
+		fmt.Printf("⚠️ Skipped the static CSS extraction: %v\n", err)
+	} else {
+		fmt.Printf("✅ Extracted the styles to %s.\n", fileName)
+	}
 	fmt.Println("\nBuild complete. You can now serve the directory using 'vortex dev'")
 }
 
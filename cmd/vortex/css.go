@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// staticCSSLinkID is the id of the <link> to the extracted CSS in index.html
+// The renderer looks for it to skip the injection of the classes already in the file
+const staticCSSLinkID = "vortex-static-css"
+
+// stylesPackage is the package of the project registering its styles in InitializeStyles
+const stylesPackage = "styles"
+
+// cssExportProgram is the native program run to export the registered styles
+// The styles package builds outside the browser, so it can run with the host GOOS/GOARCH
+const cssExportProgram = `package main
+
+import (
+	"fmt"
+	"os"
+
+	"{{.StylesImport}}"
+	"github.com/AureClai/vortex/pkg/style"
+)
+
+func main() {
+	styles.InitializeStyles()
+
+	file, err := os.Create(os.Args[1])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	defer file.Close()
+
+	if err := style.ExportCSS(file); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+`
+
+var (
+	staticCSSFilePattern = regexp.MustCompile(`^app\.[0-9a-f]+\.css$`)
+	staticCSSLinkPattern = regexp.MustCompile(`[ \t]*<link[^>]*id="` + staticCSSLinkID + `"[^>]*>\n?`)
+)
+
+// extractStaticCSS exports the registered styles of the project to a hashed app.css
+// and links it from index.html. It returns the name of the generated file.
+func extractStaticCSS() (string, error) {
+	modulePath, err := readModulePath("go.mod")
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(stylesPackage); err != nil {
+		return "", fmt.Errorf("no %s package found", stylesPackage)
+	}
+
+	css, err := exportCSS(modulePath + "/" + stylesPackage)
+	if err != nil {
+		return "", err
+	}
+
+	// The hash in the name lets the browser cache the file until the styles change
+	sum := sha256.Sum256(css)
+	fileName := fmt.Sprintf("app.%x.css", sum[:6])
+	if err := removeStaticCSS(); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(fileName, css, 0644); err != nil {
+		return "", fmt.Errorf("could not write %s: %w", fileName, err)
+	}
+
+	if err := linkStaticCSS("index.html", fileName); err != nil {
+		return "", err
+	}
+	return fileName, nil
+}
+
+// exportCSS runs the export program in a temporary directory of the project and returns the CSS
+func exportCSS(stylesImport string) ([]byte, error) {
+	dir, err := os.MkdirTemp(".", "_vortex_css")
+	if err != nil {
+		return nil, fmt.Errorf("could not create the export directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	program := strings.Replace(cssExportProgram, "{{.StylesImport}}", stylesImport, 1)
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte(program), 0644); err != nil {
+		return nil, fmt.Errorf("could not write the export program: %w", err)
+	}
+
+	output := filepath.Join(dir, "app.css")
+	runCmd := exec.Command("go", "run", "./"+filepath.Base(dir), output)
+	runCmd.Stderr = os.Stderr
+	if err := runCmd.Run(); err != nil {
+		return nil, fmt.Errorf("the export of the styles failed: %w", err)
+	}
+
+	return os.ReadFile(output)
+}
+
+// readModulePath returns the module path declared in a go.mod file
+func readModulePath(goModPath string) (string, error) {
+	file, err := os.Open(goModPath)
+	if err != nil {
+		return "", fmt.Errorf("could not open %s: %w", goModPath, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "module ") {
+			return strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, "module ")), `"`), nil
+		}
+	}
+	return "", fmt.Errorf("no module declared in %s", goModPath)
+}
+
+// removeStaticCSS removes the files generated by the previous builds
+func removeStaticCSS() error {
+	entries, err := os.ReadDir(".")
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() && staticCSSFilePattern.MatchString(entry.Name()) {
+			if err := os.Remove(entry.Name()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// linkStaticCSS points the <link> of index.html to the generated file, adding it if needed
+func linkStaticCSS(indexPath, fileName string) error {
+	content, err := os.ReadFile(indexPath)
+	if err != nil {
+		return fmt.Errorf("could not read %s: %w", indexPath, err)
+	}
+
+	html := staticCSSLinkPattern.ReplaceAllString(string(content), "")
+	link := fmt.Sprintf(`    <link rel="stylesheet" href="%s" id="%s">`+"\n", fileName, staticCSSLinkID)
+
+	index := strings.Index(html, "</head>")
+	if index < 0 {
+		return fmt.Errorf("no </head> found in %s", indexPath)
+	}
+	// Insert the link on its own line, before the line closing the head
+	index = strings.LastIndex(html[:index], "\n") + 1
+	html = html[:index] + link + html[index:]
+
+	return os.WriteFile(indexPath, []byte(html), 0644)
+}
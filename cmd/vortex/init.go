@@ -149,6 +149,9 @@ app.wasm
 # JS glue file  
 wasm_exec.js
 
+# Extracted styles
+app.*.css
+
 # Build artifacts
 dist/
 build/
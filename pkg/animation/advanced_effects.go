@@ -8,6 +8,8 @@ import (
 	"math/rand"
 	"syscall/js"
 	"time"
+
+	"github.com/AureClai/vortex/pkg/style"
 )
 
 // TextMorphEffect creates a text morphing animation
@@ -64,8 +66,9 @@ func (tme *TextMorphEffect) animateCharacterExplosion(char js.Value, delay time.
 		SetDuration(600*time.Millisecond).
 		SetDelay(delay).
 		SetEasing(EaseOutExpo).
-		Animate("transform", "translateY(0px) scale(1) rotate(0deg)",
-			fmt.Sprintf("translateY(-40px) scale(1.8) rotate(%ddeg)", rand.Intn(360)), "").
+		AnimateTransform(
+			style.NewTransform(style.TranslateY(style.Px(0)), style.Scale(1), style.Rotate(style.Degrees(0))),
+			style.NewTransform(style.TranslateY(style.Px(-40)), style.Scale(1.8), style.Rotate(style.Degrees(float64(rand.Intn(360)))))).
 		Animate("opacity", 1.0, 0.0, "").
 		OnStart(func() {
 			// Create particle burst at character position
@@ -150,7 +153,7 @@ func (re *RippleEffect) CreateRipple(x, y float64, color Color, maxRadius float6
 	ripple.Get("style").Set("border", fmt.Sprintf("2px solid %s", color.ToString()))
 	ripple.Get("style").Set("pointerEvents", "none")
 	ripple.Get("style").Set("zIndex", "10000")
-	ripple.Get("style").Set("transform", "translate(-50%, -50%)")
+	ripple.Get("style").Set("transform", style.NewTransform(style.Translate(style.Percent(-50), style.Percent(-50))).String())
 
 	document.Get("body").Call("appendChild", ripple)
 
@@ -208,8 +211,9 @@ func (me *MagneticEffect) AttractElements(elements []js.Value, centerX, centerY
 				SetDuration(duration).
 				SetDelay(delay).
 				SetEasing(EaseOutElastic).
-				Animate("transform", "translate(0px, 0px)",
-					fmt.Sprintf("translate(%.2fpx, %.2fpx)", attractX, attractY), "").
+				AnimateTransform(
+					style.NewTransform(style.Translate(style.Px(0), style.Px(0))),
+					style.NewTransform(style.Translate(style.Px(attractX), style.Px(attractY)))).
 				Build()
 
 			me.engine.AddAnimation(attractAnim)
@@ -238,14 +242,14 @@ func (fe *FloatingEffect) StartFloating(element js.Value, amplitude float64, per
 		SetElement(element).
 		SetDuration(period/2).
 		SetEasing(EaseInOutSine).
-		Animate("transform", "translateY(0px)", fmt.Sprintf("translateY(-%.2fpx)", amplitude), "").
+		AnimateTransform(style.NewTransform(style.TranslateY(style.Px(0))), style.NewTransform(style.TranslateY(style.Px(-amplitude)))).
 		Build()
 
 	floatDown := NewAnimation().
 		SetElement(element).
 		SetDuration(period/2).
 		SetEasing(EaseInOutSine).
-		Animate("transform", fmt.Sprintf("translateY(-%.2fpx)", amplitude), "translateY(0px)", "").
+		AnimateTransform(style.NewTransform(style.TranslateY(style.Px(-amplitude))), style.NewTransform(style.TranslateY(style.Px(0)))).
 		Build()
 
 	timeline := NewTimeline(fe.engine)
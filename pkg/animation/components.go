@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/AureClai/vortex/pkg/component"
+	"github.com/AureClai/vortex/pkg/style"
 	"github.com/AureClai/vortex/pkg/vdom"
 )
 
@@ -16,6 +17,7 @@ type AnimatedComponent struct {
 	child      vdom.Component
 	animations map[string]*Animation
 	engine     *AnimationEngine
+	node       *vdom.VNode // last rendered node
 	element    js.Value
 	mounted    bool
 }
@@ -44,9 +46,33 @@ func (ac *AnimatedComponent) Render() *vdom.VNode {
 	}
 	node.Props["class"] = classes
 
+	ac.node = node
 	return node
 }
 
+// OnMount binds the DOM element of the rendered node once it is in the document
+func (ac *AnimatedComponent) OnMount() {
+	ac.element = ac.node.Host().Element
+	ac.mounted = true
+}
+
+// OnUnmount cancels the animations still running on the element
+func (ac *AnimatedComponent) OnUnmount() {
+	for id := range ac.animations {
+		ac.engine.RemoveAnimation(id)
+		delete(ac.animations, id)
+	}
+	ac.element = js.Undefined()
+	ac.mounted = false
+}
+
+// play binds the animation to the element and starts it
+func (ac *AnimatedComponent) play(anim *Animation) {
+	anim.element = ac.element
+	ac.animations[anim.ID] = anim
+	ac.engine.AddAnimation(anim)
+}
+
 // FadeIn creates a fade-in animation component
 type FadeIn struct {
 	*AnimatedComponent
@@ -92,14 +118,15 @@ func (f *FadeIn) Render() *vdom.VNode {
 	node.Props["class"] = classes
 	node.Props["style"] = "opacity: 0;"
 
-	// Start animation when component mounts
-	f.engine.OnNextFrame(func() {
-		f.startFadeIn()
-	})
-
 	return node
 }
 
+// OnMount starts the fade-in once the element is in the document
+func (f *FadeIn) OnMount() {
+	f.AnimatedComponent.OnMount()
+	f.startFadeIn()
+}
+
 // startFadeIn starts the fade-in animation
 func (f *FadeIn) startFadeIn() {
 	anim := &Animation{
@@ -117,7 +144,7 @@ func (f *FadeIn) startFadeIn() {
 		},
 	}
 
-	f.engine.AddAnimation(anim)
+	f.play(anim)
 }
 
 // SlideIn creates a slide-in animation component
@@ -175,28 +202,17 @@ func (s *SlideIn) Render() *vdom.VNode {
 	node.Props["class"] = classes
 
 	// Set initial transform based on direction
-	var initialTransform string
-	switch s.direction {
-	case "left":
-		initialTransform = fmt.Sprintf("translateX(-%dpx)", s.distance)
-	case "right":
-		initialTransform = fmt.Sprintf("translateX(%dpx)", s.distance)
-	case "up":
-		initialTransform = fmt.Sprintf("translateY(-%dpx)", s.distance)
-	case "down":
-		initialTransform = fmt.Sprintf("translateY(%dpx)", s.distance)
-	}
-
-	node.Props["style"] = fmt.Sprintf("transform: %s;", initialTransform)
-
-	// Start animation when component mounts
-	s.engine.OnNextFrame(func() {
-		s.startSlideIn()
-	})
+	node.Props["style"] = "transform: " + s.getInitialTransform().String() + ";"
 
 	return node
 }
 
+// OnMount starts the slide-in once the element is in the document
+func (s *SlideIn) OnMount() {
+	s.AnimatedComponent.OnMount()
+	s.startSlideIn()
+}
+
 // startSlideIn starts the slide-in animation
 func (s *SlideIn) startSlideIn() {
 	anim := &Animation{
@@ -208,28 +224,29 @@ func (s *SlideIn) startSlideIn() {
 			{
 				Property: "transform",
 				From:     s.getInitialTransform(),
-				To:       "translateX(0px) translateY(0px)",
+				To:       style.NewTransform(), // The translation back to 0, see TransformValue.Interpolate
 				Unit:     "",
 			},
 		},
 	}
 
-	s.engine.AddAnimation(anim)
+	s.play(anim)
 }
 
 // getInitialTransform returns the initial transform based on direction
-func (s *SlideIn) getInitialTransform() string {
+func (s *SlideIn) getInitialTransform() style.TransformValue {
+	distance := float64(s.distance)
 	switch s.direction {
 	case "left":
-		return fmt.Sprintf("translateX(-%dpx)", s.distance)
+		return style.NewTransform(style.TranslateX(style.Px(-distance)))
 	case "right":
-		return fmt.Sprintf("translateX(%dpx)", s.distance)
+		return style.NewTransform(style.TranslateX(style.Px(distance)))
 	case "up":
-		return fmt.Sprintf("translateY(-%dpx)", s.distance)
+		return style.NewTransform(style.TranslateY(style.Px(-distance)))
 	case "down":
-		return fmt.Sprintf("translateY(%dpx)", s.distance)
+		return style.NewTransform(style.TranslateY(style.Px(distance)))
 	default:
-		return "translateX(0px) translateY(0px)"
+		return style.NewTransform()
 	}
 }
 
@@ -281,16 +298,17 @@ func (sc *ScaleIn) Render() *vdom.VNode {
 	// Add scale-in specific classes
 	classes := node.Props["class"].(string) + " scale-in"
 	node.Props["class"] = classes
-	node.Props["style"] = fmt.Sprintf("transform: scale(%f);", sc.fromScale)
-
-	// Start animation when component mounts
-	sc.engine.OnNextFrame(func() {
-		sc.startScaleIn()
-	})
+	node.Props["style"] = "transform: " + style.NewTransform(style.Scale(sc.fromScale)).String() + ";"
 
 	return node
 }
 
+// OnMount starts the scale-in once the element is in the document
+func (sc *ScaleIn) OnMount() {
+	sc.AnimatedComponent.OnMount()
+	sc.startScaleIn()
+}
+
 // startScaleIn starts the scale-in animation
 func (sc *ScaleIn) startScaleIn() {
 	anim := &Animation{
@@ -301,14 +319,14 @@ func (sc *ScaleIn) startScaleIn() {
 		Properties: []PropertyAnimation{
 			{
 				Property: "transform",
-				From:     fmt.Sprintf("scale(%f)", sc.fromScale),
-				To:       fmt.Sprintf("scale(%f)", sc.toScale),
+				From:     style.NewTransform(style.Scale(sc.fromScale)),
+				To:       style.NewTransform(style.Scale(sc.toScale)),
 				Unit:     "",
 			},
 		},
 	}
 
-	sc.engine.AddAnimation(anim)
+	sc.play(anim)
 }
 
 // Stagger creates staggered animations for multiple components
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/AureClai/vortex/pkg/renderer"
+	"github.com/AureClai/vortex/pkg/style"
 )
 
 // EasingFunc defines the signature for easing functions
@@ -214,6 +215,14 @@ func (e *AnimationEngine) defaultInterpolate(from, to interface{}, progress floa
 	case string:
 		// Handle color interpolation, etc.
 		return e.interpolateString(f, to.(string), progress)
+	case style.TransformValue:
+		if t, ok := to.(style.TransformValue); ok {
+			return f.Interpolate(t, progress)
+		}
+	case style.FilterValue:
+		if t, ok := to.(style.FilterValue); ok {
+			return f.Interpolate(t, progress)
+		}
 	}
 
 	// Fallback: return target value when progress >= 0.5
@@ -240,8 +249,8 @@ func (e *AnimationEngine) applyPropertyToElement(element js.Value, prop *Propert
 	switch prop.Property {
 	case "opacity":
 		style.Set("opacity", fmt.Sprintf("%v", prop.Current))
-	case "transform":
-		style.Set("transform", fmt.Sprintf("%v", prop.Current))
+	case "transform", "filter":
+		style.Set(prop.Property, fmt.Sprintf("%v", prop.Current))
 	case "left", "top", "width", "height":
 		value := fmt.Sprintf("%v%s", prop.Current, prop.Unit)
 		style.Set(prop.Property, value)
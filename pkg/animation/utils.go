@@ -5,8 +5,11 @@ package animation
 import (
 	"fmt"
 	"math"
+	"slices"
 	"syscall/js"
 	"time"
+
+	"github.com/AureClai/vortex/pkg/style"
 )
 
 // AnimationBuilder provides a fluent interface for creating animations
@@ -106,41 +109,84 @@ func (ab *AnimationBuilder) FadeOut() *AnimationBuilder {
 	return ab.Animate("opacity", 1.0, 0.0, "")
 }
 
-// MoveTo animates position
+// MoveTo moves the element by (x, y) pixels with a translation, without changing the layout
 func (ab *AnimationBuilder) MoveTo(x, y float64) *AnimationBuilder {
-	ab.Animate("left", 0.0, x, "px")
-	ab.Animate("top", 0.0, y, "px")
-	return ab
+	return ab.AnimateTransform(
+		style.NewTransform(style.Translate(style.Px(0), style.Px(0))),
+		style.NewTransform(style.Translate(style.Px(x), style.Px(y))),
+	)
+}
+
+// AnimateTransform animates the transform between the typed values
+// The transforms animated by the same builder are composed in order instead of replacing each other:
+// ScaleTo(1.5).RotateTo(45) animates from "scale(1) rotate(0deg)" to "scale(1.5) rotate(45deg)"
+// A function already animated is replaced: ScaleTo(1.5).ScaleTo(2) animates to "scale(2)" alone
+func (ab *AnimationBuilder) AnimateTransform(from, to style.TransformValue) *AnimationBuilder {
+	for i := range ab.animation.Properties {
+		prop := &ab.animation.Properties[i]
+		previousFrom, fromOk := prop.From.(style.TransformValue)
+		previousTo, toOk := prop.To.(style.TransformValue)
+		if prop.Property == "transform" && fromOk && toOk {
+			prop.From = mergeTransform(previousFrom, from)
+			prop.To = mergeTransform(previousTo, to)
+			return ab
+		}
+	}
+	return ab.Animate("transform", from, to, "")
+}
+
+// mergeTransform returns the transform with the functions of next, in place of the functions of the same name
+// The other functions of next follow, in order
+func mergeTransform(transform, next style.TransformValue) style.TransformValue {
+	merged := slices.Clone(transform)
+	for _, function := range next {
+		replaced := false
+		for i := range merged {
+			if merged[i].Name == function.Name {
+				merged[i], replaced = function, true
+				break
+			}
+		}
+		if !replaced {
+			merged = append(merged, function)
+		}
+	}
+	return merged
+}
+
+// AnimateFilter animates the filter between the typed values
+func (ab *AnimationBuilder) AnimateFilter(from, to style.FilterValue) *AnimationBuilder {
+	return ab.Animate("filter", from, to, "")
 }
 
 // ScaleTo animates scale
 func (ab *AnimationBuilder) ScaleTo(scale float64) *AnimationBuilder {
-	return ab.Animate("transform", "scale(1)", fmt.Sprintf("scale(%f)", scale), "")
+	return ab.AnimateTransform(style.NewTransform(style.Scale(1)), style.NewTransform(style.Scale(scale)))
 }
 
 // RotateTo animates rotation
 func (ab *AnimationBuilder) RotateTo(degrees float64) *AnimationBuilder {
-	return ab.Animate("transform", "rotate(0deg)", fmt.Sprintf("rotate(%fdeg)", degrees), "")
+	return ab.AnimateTransform(style.NewTransform(style.Rotate(style.Degrees(0))), style.NewTransform(style.Rotate(style.Degrees(degrees))))
 }
 
 // SlideLeft slides element to the left
 func (ab *AnimationBuilder) SlideLeft(distance float64) *AnimationBuilder {
-	return ab.Animate("transform", "translateX(0px)", fmt.Sprintf("translateX(-%fpx)", distance), "")
+	return ab.AnimateTransform(style.NewTransform(style.TranslateX(style.Px(0))), style.NewTransform(style.TranslateX(style.Px(-distance))))
 }
 
 // SlideRight slides element to the right
 func (ab *AnimationBuilder) SlideRight(distance float64) *AnimationBuilder {
-	return ab.Animate("transform", "translateX(0px)", fmt.Sprintf("translateX(%fpx)", distance), "")
+	return ab.AnimateTransform(style.NewTransform(style.TranslateX(style.Px(0))), style.NewTransform(style.TranslateX(style.Px(distance))))
 }
 
 // SlideUp slides element up
 func (ab *AnimationBuilder) SlideUp(distance float64) *AnimationBuilder {
-	return ab.Animate("transform", "translateY(0px)", fmt.Sprintf("translateY(-%fpx)", distance), "")
+	return ab.AnimateTransform(style.NewTransform(style.TranslateY(style.Px(0))), style.NewTransform(style.TranslateY(style.Px(-distance))))
 }
 
 // SlideDown slides element down
 func (ab *AnimationBuilder) SlideDown(distance float64) *AnimationBuilder {
-	return ab.Animate("transform", "translateY(0px)", fmt.Sprintf("translateY(%fpx)", distance), "")
+	return ab.AnimateTransform(style.NewTransform(style.TranslateY(style.Px(0))), style.NewTransform(style.TranslateY(style.Px(distance))))
 }
 
 // Build returns the constructed animation
@@ -1 +1,177 @@
+//go:build js && wasm
+
 package async
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"syscall/js"
+)
+
+// Client calls HTTP APIs with the fetch API of the browser
+//
+// Usage examples :
+//
+//	api := async.NewClient("/api")
+//	api.Header["Authorization"] = "Bearer " + token
+//
+//	var users []User
+//	err := api.Get(ctx, "/users", &users)
+//	err = api.Post(ctx, "/users", NewUser{Name: "Ada"}, &created)
+type Client struct {
+	BaseURL string
+	Header  map[string]string // Sent with every request
+}
+
+// NewClient creates a client for the API at the base URL
+func NewClient(baseURL string) *Client {
+	return &Client{
+		BaseURL: strings.TrimSuffix(baseURL, "/"),
+		Header:  map[string]string{"Accept": "application/json"},
+	}
+}
+
+// Get sends a GET request and decodes the JSON response in out, unless out is nil
+func (c *Client) Get(ctx context.Context, url string, out interface{}) error {
+	return c.send(ctx, "GET", url, nil, out)
+}
+
+// Post sends in as JSON and decodes the JSON response in out, unless out is nil
+func (c *Client) Post(ctx context.Context, url string, in, out interface{}) error {
+	return c.send(ctx, "POST", url, in, out)
+}
+
+// Put sends in as JSON and decodes the JSON response in out, unless out is nil
+func (c *Client) Put(ctx context.Context, url string, in, out interface{}) error {
+	return c.send(ctx, "PUT", url, in, out)
+}
+
+// Patch sends in as JSON and decodes the JSON response in out, unless out is nil
+func (c *Client) Patch(ctx context.Context, url string, in, out interface{}) error {
+	return c.send(ctx, "PATCH", url, in, out)
+}
+
+// Delete sends a DELETE request and decodes the JSON response in out, unless out is nil
+func (c *Client) Delete(ctx context.Context, url string, out interface{}) error {
+	return c.send(ctx, "DELETE", url, nil, out)
+}
+
+func (c *Client) send(ctx context.Context, method, url string, in, out interface{}) error {
+	response, err := c.Do(ctx, &Request{Method: method, URL: url, Body: in})
+	if err != nil {
+		return err
+	}
+	if out == nil || len(response.Body) == 0 {
+		return nil
+	}
+	if err := response.JSON(out); err != nil {
+		return fmt.Errorf("could not decode the response of %s %s: %w", method, url, err)
+	}
+	return nil
+}
+
+// Do sends the request and reads the response
+// A response with an error status is returned with an *HTTPError. The request is aborted
+// when the context is done, the error is then the one of the context.
+func (c *Client) Do(ctx context.Context, req *Request) (*Response, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	init := js.Global().Get("Object").New()
+	method := req.Method
+	if method == "" {
+		method = "GET"
+	}
+	init.Set("method", method)
+
+	contentType := ""
+	if req.Body != nil {
+		body, bodyType, err := encodeBody(req.Body)
+		if err != nil {
+			return nil, fmt.Errorf("could not encode the body of %s %s: %w", method, req.URL, err)
+		}
+		contentType = bodyType
+		init.Set("body", bodyValue(body))
+	}
+
+	headers := js.Global().Get("Headers").New()
+	for name, value := range requestHeaders(c.Header, req.Header, contentType) {
+		headers.Call("set", name, value)
+	}
+	init.Set("headers", headers)
+
+	// Abort the request when the context is done
+	if ctx.Done() != nil {
+		controller := js.Global().Get("AbortController").New()
+		init.Set("signal", controller.Get("signal"))
+		finished := make(chan struct{})
+		defer close(finished)
+		go func() {
+			select {
+			case <-ctx.Done():
+				controller.Call("abort")
+			case <-finished:
+			}
+		}()
+	}
+
+	url := req.URL
+	if !strings.Contains(url, "://") {
+		url = c.BaseURL + url
+	}
+
+	response, err := Await(js.Global().Call("fetch", url, init))
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("%s %s failed: %w", method, url, err)
+	}
+
+	buffer, err := Await(response.Call("arrayBuffer"))
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("could not read the response of %s %s: %w", method, url, err)
+	}
+	bytes := js.Global().Get("Uint8Array").New(buffer)
+	result := &Response{
+		Status:     response.Get("status").Int(),
+		StatusText: response.Get("statusText").String(),
+		Header:     readHeaders(response.Get("headers")),
+		Body:       make([]byte, bytes.Length()),
+	}
+	js.CopyBytesToGo(result.Body, bytes)
+
+	if result.Status >= 400 {
+		return result, &HTTPError{Status: result.Status, StatusText: result.StatusText, Body: result.Body}
+	}
+	return result, nil
+}
+
+// bodyValue returns the encoded body as a JS value: a string, or a Uint8Array for bytes
+func bodyValue(body interface{}) js.Value {
+	if b, ok := body.([]byte); ok {
+		array := js.Global().Get("Uint8Array").New(len(b))
+		js.CopyBytesToJS(array, b)
+		return array
+	}
+	return js.ValueOf(body)
+}
+
+// readHeaders copies the headers of a response
+func readHeaders(headers js.Value) map[string]string {
+	result := make(map[string]string)
+	collect := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		if len(args) >= 2 {
+			result[strings.ToLower(args[1].String())] = args[0].String()
+		}
+		return nil
+	})
+	defer collect.Release()
+	headers.Call("forEach", collect)
+	return result
+}
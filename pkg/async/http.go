@@ -0,0 +1,127 @@
+package async
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Errors matched by the *HTTPError of the corresponding status, with errors.Is
+var (
+	ErrBadRequest   = errors.New("bad request")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	ErrRateLimited  = errors.New("rate limited")
+	ErrServer       = errors.New("server error")
+)
+
+// HTTPError is the error of a response with an error status (4xx or 5xx)
+//
+// Usage examples :
+//
+//	if errors.Is(err, async.ErrNotFound) {
+//	    ...
+//	}
+type HTTPError struct {
+	Status     int
+	StatusText string
+	Body       []byte
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("HTTP %d %s", e.Status, e.StatusText)
+}
+
+// Is maps the status to the errors of the package
+func (e *HTTPError) Is(target error) bool {
+	switch e.Status {
+	case 400:
+		return target == ErrBadRequest
+	case 401:
+		return target == ErrUnauthorized
+	case 403:
+		return target == ErrForbidden
+	case 404:
+		return target == ErrNotFound
+	case 409:
+		return target == ErrConflict
+	case 429:
+		return target == ErrRateLimited
+	}
+	return e.Status >= 500 && target == ErrServer
+}
+
+// Request is an HTTP request sent by a Client
+type Request struct {
+	Method string
+	URL    string            // Relative to the BaseURL of the client, unless absolute
+	Header map[string]string // Added to the headers of the client
+	Body   interface{}       // Encoded in JSON, unless it is a string or a []byte
+}
+
+// Response is the response of a request, with its body read
+type Response struct {
+	Status     int
+	StatusText string
+	Header     map[string]string // Names in lower case
+	Body       []byte
+}
+
+// JSON decodes the body in v
+func (r *Response) JSON(v interface{}) error {
+	return json.Unmarshal(r.Body, v)
+}
+
+// Text returns the body as a string
+func (r *Response) Text() string {
+	return string(r.Body)
+}
+
+// encodeBody returns the body to send and its content type: the strings and bytes as is, the rest in JSON
+// The body is a string or a []byte. The content type is empty when the body is sent as is,
+// the browser then chooses it
+func encodeBody(body interface{}) (encoded interface{}, contentType string, err error) {
+	switch b := body.(type) {
+	case string, []byte:
+		return b, "", nil
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, "", err
+	}
+	return string(data), "application/json", nil
+}
+
+// requestHeaders merges the headers of the client and of the request, the request ones winning
+// The content type of the body is added unless a header sets it. The names are compared
+// in any case, as HTTP does
+func requestHeaders(client, request map[string]string, contentType string) map[string]string {
+	headers := make(map[string]string)
+	set := func(name, value string) {
+		for existing := range headers {
+			if strings.EqualFold(existing, name) {
+				delete(headers, existing)
+			}
+		}
+		headers[name] = value
+	}
+	for name, value := range client {
+		set(name, value)
+	}
+	for name, value := range request {
+		set(name, value)
+	}
+	if contentType == "" {
+		return headers
+	}
+	for name := range headers {
+		if strings.EqualFold(name, "Content-Type") {
+			return headers
+		}
+	}
+	headers["Content-Type"] = contentType
+	return headers
+}
@@ -0,0 +1,90 @@
+package async
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestHTTPErrorIs(t *testing.T) {
+	sentinels := []error{ErrBadRequest, ErrUnauthorized, ErrForbidden, ErrNotFound, ErrConflict, ErrRateLimited, ErrServer}
+	tests := []struct {
+		status int
+		want   error // nil when no sentinel matches
+	}{
+		{400, ErrBadRequest},
+		{401, ErrUnauthorized},
+		{403, ErrForbidden},
+		{404, ErrNotFound},
+		{409, ErrConflict},
+		{429, ErrRateLimited},
+		{500, ErrServer},
+		{503, ErrServer},
+		{418, nil},
+		{302, nil},
+	}
+	for _, tt := range tests {
+		// Wrapped, as returned by the client
+		err := fmt.Errorf("GET /api: %w", &HTTPError{Status: tt.status})
+		for _, sentinel := range sentinels {
+			if got, want := errors.Is(err, sentinel), sentinel == tt.want; got != want {
+				t.Errorf("status %d: errors.Is(err, %q) = %v, want %v", tt.status, sentinel, got, want)
+			}
+		}
+	}
+}
+
+func TestEncodeBody(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        interface{}
+		want        interface{}
+		contentType string
+	}{
+		{"string as is", "a=1&b=2", "a=1&b=2", ""},
+		{"bytes as is", []byte{1, 2}, []byte{1, 2}, ""},
+		{"struct in JSON", struct{ Name string }{"Ada"}, `{"Name":"Ada"}`, "application/json"},
+		{"map in JSON", map[string]int{"n": 1}, `{"n":1}`, "application/json"},
+		{"number in JSON", 42, "42", "application/json"},
+	}
+	for _, tt := range tests {
+		encoded, contentType, err := encodeBody(tt.body)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(encoded, tt.want) || contentType != tt.contentType {
+			t.Errorf("%s: got %#v %q, want %#v %q", tt.name, encoded, contentType, tt.want, tt.contentType)
+		}
+	}
+
+	if _, _, err := encodeBody(make(chan int)); err == nil {
+		t.Error("encoded a channel")
+	}
+}
+
+func TestRequestHeaders(t *testing.T) {
+	client := map[string]string{"Accept": "application/json", "Authorization": "Bearer a"}
+
+	tests := []struct {
+		name        string
+		request     map[string]string
+		contentType string
+		want        map[string]string
+	}{
+		{"client headers", nil, "",
+			map[string]string{"Accept": "application/json", "Authorization": "Bearer a"}},
+		{"JSON body", nil, "application/json",
+			map[string]string{"Accept": "application/json", "Authorization": "Bearer a", "Content-Type": "application/json"}},
+		{"request wins in any case", map[string]string{"authorization": "Bearer b"}, "",
+			map[string]string{"Accept": "application/json", "authorization": "Bearer b"}},
+		{"content type set by the request", map[string]string{"content-type": "application/merge-patch+json"}, "application/json",
+			map[string]string{"Accept": "application/json", "Authorization": "Bearer a", "content-type": "application/merge-patch+json"}},
+	}
+	for _, tt := range tests {
+		if got := requestHeaders(client, tt.request, tt.contentType); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
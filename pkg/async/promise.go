@@ -1 +1,93 @@
+//go:build js && wasm
+
+// Package async bridges the asynchronous APIs of the browser to Go.
+//
+// Await waits for a JS Promise, Client calls HTTP APIs with fetch, and the timers
+// (SetTimeout, SetInterval, Debounce, Throttle) schedule Go functions on the event loop.
+// Resource renders the loading, error and data states of an asynchronous request.
+//
+// Basic Usage:
+//
+//	go func() {
+//	    var user User
+//	    if err := async.NewClient("/api").Get(ctx, "/users/42", &user); err != nil {
+//	        ...
+//	    }
+//	    app.SetState(AppState{User: user})
+//	}()
+//
+// Await, and the Client built on it, block the calling goroutine until the browser settles
+// the promise. They must run in their own goroutine, never in an event handler or a timer
+// callback: those run on the event loop the promise needs to settle.
 package async
+
+import (
+	"syscall/js"
+)
+
+// JSError is a JS value a promise was rejected with
+type JSError struct {
+	Value js.Value
+}
+
+func (e *JSError) Error() string {
+	if e.Value.Type() == js.TypeObject {
+		if message := e.Value.Get("message"); message.Type() == js.TypeString {
+			if name := e.Value.Get("name"); name.Type() == js.TypeString {
+				return name.String() + ": " + message.String()
+			}
+			return message.String()
+		}
+	}
+	return e.Value.String()
+}
+
+// Name returns the name of the JS error, such as "AbortError" or "TypeError"
+func (e *JSError) Name() string {
+	if e.Value.Type() == js.TypeObject && e.Value.Get("name").Type() == js.TypeString {
+		return e.Value.Get("name").String()
+	}
+	return ""
+}
+
+// Await blocks until the promise settles and returns its value, or the rejection as a *JSError
+// A value which is not a promise is returned as is
+//
+// Usage examples :
+//
+//	response, err := async.Await(js.Global().Call("fetch", "/api/health"))
+func Await(promise js.Value) (js.Value, error) {
+	if promise.Type() != js.TypeObject || promise.Get("then").Type() != js.TypeFunction {
+		return promise, nil
+	}
+
+	type result struct {
+		value js.Value
+		err   error
+	}
+	done := make(chan result, 1)
+
+	var onFulfilled, onRejected js.Func
+	onFulfilled = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		done <- result{value: argument(args)}
+		return nil
+	})
+	onRejected = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		done <- result{err: &JSError{Value: argument(args)}}
+		return nil
+	})
+	defer onFulfilled.Release()
+	defer onRejected.Release()
+
+	promise.Call("then", onFulfilled, onRejected)
+	r := <-done
+	return r.value, r.err
+}
+
+// argument returns the first argument of a callback, undefined when there is none
+func argument(args []js.Value) js.Value {
+	if len(args) == 0 {
+		return js.Undefined()
+	}
+	return args[0]
+}
@@ -0,0 +1,359 @@
+package async
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/AureClai/vortex/pkg/vdom"
+)
+
+// Loader loads the data of a request, identified by its key (such as its URL)
+// It must return when the context is done
+type Loader[T any] func(ctx context.Context, key string) (T, error)
+
+// ResourceState is the state of a Resource
+type ResourceState[T any] struct {
+	Key     string
+	Loading bool  // A request is running, the previous data may still be shown
+	Data    T     // Data of the last successful request
+	HasData bool  // Data has been loaded for the key
+	Err     error // Error of the last request
+}
+
+// ResourceViews render the states of a Resource
+type ResourceViews[T any] struct {
+	Loading func() *vdom.VNode                        // While nothing has been loaded for the key
+	Error   func(err error, retry func()) *vdom.VNode // When the request failed, nothing being loaded
+	Data    func(data T, refreshing bool) *vdom.VNode // Once loaded, also while refreshing
+}
+
+// RetryPolicy retries a failed request after a delay growing exponentially
+// The zero value does not retry
+type RetryPolicy struct {
+	Attempts int           // Number of retries after the first failure
+	Delay    time.Duration // Delay before the first retry, doubled for each next one
+	MaxDelay time.Duration // Upper bound of the delay, none when 0
+}
+
+// delay returns the delay before the retry number attempt (0 for the first retry)
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	delay := p.Delay << attempt
+	if p.MaxDelay > 0 && (delay > p.MaxDelay || delay <= 0) {
+		delay = p.MaxDelay
+	}
+	return delay
+}
+
+// Resource loads data asynchronously and renders its loading, error and data states
+//
+// The request changes with Load: the request of the previous key is cancelled, so a slow
+// response never overwrites a newer one. The results are shared through a Cache, one request
+// being made for the components loading the same key at the same time.
+//
+// Usage examples :
+//
+//	user := async.NewResource(loadUser, async.ResourceViews[User]{
+//	    Loading: func() *vdom.VNode { return spinner.Render() },
+//	    Error: func(err error, retry func()) *vdom.VNode { return errorBox(err, retry) },
+//	    Data: func(u User, refreshing bool) *vdom.VNode { return profile(u) },
+//	}, func() { r.ScheduleUpdate(user) })
+//	user.Retry = async.RetryPolicy{Attempts: 3, Delay: 500 * time.Millisecond}
+//
+//	// In the Render of the parent, when its inputs may have changed:
+//	user.Load(fmt.Sprintf("/api/users/%d", id))
+//	page.AddChild(user)
+type Resource[T any] struct {
+	vdom.StatefulComponentBase[ResourceState[T]]
+
+	Retry RetryPolicy
+	Cache *Cache // DefaultCache unless set
+
+	loader     Loader[T]
+	views      ResourceViews[T]
+	mutex      sync.Mutex
+	cancel     context.CancelFunc // Cancels the running request
+	generation int                // Incremented by each request, to ignore the stale ones
+}
+
+// NewResource creates a resource loading with the loader and rendering with the views
+// reRender is called after every state change, usually to schedule an update of the resource.
+// It must not call Load, Refresh or Cancel itself: the resource is locked while it runs
+func NewResource[T any](loader Loader[T], views ResourceViews[T], reRender func()) *Resource[T] {
+	return &Resource[T]{
+		StatefulComponentBase: vdom.NewStatefulComponent("div", ResourceState[T]{}, reRender),
+		loader:                loader,
+		views:                 views,
+	}
+}
+
+// Load loads the key, unless it is already the current one, loading, loaded or failed
+// The request of the previous key is cancelled
+func (r *Resource[T]) Load(key string) {
+	r.start(key, false)
+}
+
+// Refresh loads the current key again, bypassing the cache
+// The data stays rendered while refreshing. It is also the retry of the Error view
+func (r *Resource[T]) Refresh() {
+	r.start("", true)
+}
+
+// Cancel cancels the running request
+// The resource stops loading: the next Load of the same key requests it again
+func (r *Resource[T]) Cancel() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.cancel != nil {
+		r.cancel()
+		r.cancel = nil
+	}
+	r.generation++
+
+	if state := r.State(); state.Loading {
+		state.Loading = false
+		r.SetState(state)
+	}
+}
+
+// start cancels the running request and starts the request of the key, or of the current key when fresh
+// The state is read once under the mutex: a Load of the current key does nothing when it is
+// loading, loaded or failed, even when several goroutines load it at the same time
+func (r *Resource[T]) start(key string, fresh bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	state := r.State()
+	if fresh {
+		key = state.Key
+	} else if state.Key == key && (state.Loading || state.HasData || state.Err != nil) {
+		return
+	}
+
+	if r.cancel != nil {
+		r.cancel()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+	r.generation++
+	generation := r.generation
+
+	if state.Key != key {
+		state = ResourceState[T]{Key: key}
+	}
+	state.Loading = true
+	state.Err = nil
+
+	// A cached result is rendered at once, without a loading state
+	cache := r.cache()
+	if !fresh {
+		if value, ok := cache.get(r.cacheKey(key)); ok {
+			cancel()
+			state.Loading = false
+			state.Data, state.HasData = value.(T)
+			r.SetState(state)
+			return
+		}
+	}
+	r.SetState(state)
+
+	go func() {
+		data, err := r.load(ctx, cache, key, fresh)
+		cancel()
+
+		// The state is only written by the latest request, under the mutex:
+		// a response arriving after a newer Load or a Cancel is dropped
+		r.mutex.Lock()
+		defer r.mutex.Unlock()
+		if generation != r.generation {
+			return
+		}
+		r.cancel = nil
+
+		state := r.State()
+		state.Loading = false
+		state.Err = err
+		if err == nil {
+			state.Data, state.HasData = data, true
+		}
+		r.SetState(state)
+	}()
+}
+
+// load runs the request through the cache, retrying according to the policy
+func (r *Resource[T]) load(ctx context.Context, cache *Cache, key string, fresh bool) (T, error) {
+	for attempt := 0; ; attempt++ {
+		value, err := cache.load(ctx, r.cacheKey(key), fresh, func(ctx context.Context) (interface{}, error) {
+			return r.loader(ctx, key)
+		})
+		if err == nil {
+			data, _ := value.(T)
+			return data, nil
+		}
+		if ctx.Err() != nil || attempt >= r.Retry.Attempts {
+			var zero T
+			return zero, err
+		}
+
+		select {
+		case <-time.After(r.Retry.delay(attempt)):
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		}
+		fresh = true
+	}
+}
+
+func (r *Resource[T]) cache() *Cache {
+	if r.Cache != nil {
+		return r.Cache
+	}
+	return DefaultCache
+}
+
+// cacheKey returns the key of the results of the resource in the cache
+// The results are kept by type too: resources of different types never share them
+func (r *Resource[T]) cacheKey(key string) cacheKey {
+	return cacheKey{key: key, typ: reflect.TypeFor[T]()}
+}
+
+// Render renders the view of the current state
+func (r *Resource[T]) Render() *vdom.VNode {
+	state := r.State()
+	switch {
+	case state.HasData && r.views.Data != nil:
+		return r.views.Data(state.Data, state.Loading)
+	case state.Err != nil && !state.Loading && r.views.Error != nil:
+		return r.views.Error(state.Err, r.Refresh)
+	case r.views.Loading != nil:
+		return r.views.Loading()
+	}
+	return vdom.NewFragment()
+}
+
+// DefaultCache is the cache shared by the resources without their own
+var DefaultCache = NewCache(0)
+
+// Cache holds the results of the requests by key and type, and the requests running
+// Only the successful results are kept
+type Cache struct {
+	TTL time.Duration // Time a result is kept, forever when 0
+
+	mutex    sync.Mutex
+	entries  map[cacheKey]cacheEntry
+	inflight map[cacheKey]*sharedCall
+}
+
+// cacheKey identifies a result: the key of the request and the type of the data
+type cacheKey struct {
+	key string
+	typ reflect.Type
+}
+
+type cacheEntry struct {
+	value  interface{}
+	stored time.Time
+}
+
+// sharedCall is a request shared by the resources loading the same key
+// It is cancelled once none of them waits for it anymore
+type sharedCall struct {
+	done    chan struct{}
+	value   interface{}
+	err     error
+	waiters int
+	cancel  context.CancelFunc
+}
+
+// NewCache creates a cache keeping the results for the ttl, forever when 0
+func NewCache(ttl time.Duration) *Cache {
+	return &Cache{
+		TTL:      ttl,
+		entries:  make(map[cacheKey]cacheEntry),
+		inflight: make(map[cacheKey]*sharedCall),
+	}
+}
+
+// Invalidate removes the results of the key, of every type, the next Load requests it again
+func (c *Cache) Invalidate(key string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for entryKey := range c.entries {
+		if entryKey.key == key {
+			delete(c.entries, entryKey)
+		}
+	}
+}
+
+// Clear removes all the results
+func (c *Cache) Clear() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.entries = make(map[cacheKey]cacheEntry)
+}
+
+func (c *Cache) get(key cacheKey) (interface{}, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.lookup(key)
+}
+
+// lookup returns the result of the key if it has not expired, the mutex being held
+func (c *Cache) lookup(key cacheKey) (interface{}, bool) {
+	entry, ok := c.entries[key]
+	if !ok || c.TTL > 0 && time.Since(entry.stored) > c.TTL {
+		return nil, false
+	}
+	return entry.value, true
+}
+
+// load returns the cached result of the key, or joins the running request, or starts it
+func (c *Cache) load(ctx context.Context, key cacheKey, fresh bool, fetch func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	c.mutex.Lock()
+	if !fresh {
+		if value, ok := c.lookup(key); ok {
+			c.mutex.Unlock()
+			return value, nil
+		}
+	}
+	call, running := c.inflight[key]
+	if !running {
+		callCtx, cancel := context.WithCancel(context.Background())
+		call = &sharedCall{done: make(chan struct{}), cancel: cancel}
+		c.inflight[key] = call
+		go func() {
+			value, err := fetch(callCtx)
+			c.mutex.Lock()
+			call.value, call.err = value, err
+			if err == nil {
+				c.entries[key] = cacheEntry{value: value, stored: time.Now()}
+			}
+			if c.inflight[key] == call {
+				delete(c.inflight, key)
+			}
+			c.mutex.Unlock()
+			cancel()
+			close(call.done)
+		}()
+	}
+	call.waiters++
+	c.mutex.Unlock()
+
+	select {
+	case <-call.done:
+		return call.value, call.err
+	case <-ctx.Done():
+		c.mutex.Lock()
+		call.waiters--
+		if call.waiters == 0 {
+			call.cancel()
+			if c.inflight[key] == call {
+				delete(c.inflight, key)
+			}
+		}
+		c.mutex.Unlock()
+		return nil, ctx.Err()
+	}
+}
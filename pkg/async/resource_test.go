@@ -0,0 +1,262 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestResource creates a resource with its own cache, not re-rendered
+func newTestResource[T any](loader Loader[T], cache *Cache) *Resource[T] {
+	r := NewResource(loader, ResourceViews[T]{}, func() {})
+	r.Cache = cache
+	return r
+}
+
+// stateOf reads the state of the resource, written by its requests under the mutex
+func stateOf[T any](r *Resource[T]) ResourceState[T] {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.State()
+}
+
+// waitFor fails the test when the condition is still false after a second
+func waitFor(t *testing.T, what string, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// blockingLoader returns a loader waiting for the release channel, or for its context
+func blockingLoader(calls *atomic.Int32, release <-chan struct{}) Loader[string] {
+	return func(ctx context.Context, key string) (string, error) {
+		calls.Add(1)
+		select {
+		case <-release:
+			return "data of " + key, nil
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+}
+
+func TestResourceCancel(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	cache := NewCache(0)
+	r := newTestResource(blockingLoader(&calls, release), cache)
+
+	r.Load("a")
+	if state := stateOf(r); !state.Loading || state.Key != "a" {
+		t.Fatalf("state = %+v, want loading a", state)
+	}
+	waitFor(t, "the first request", func() bool { return calls.Load() == 1 })
+
+	r.Cancel()
+	if state := stateOf(r); state.Loading {
+		t.Fatalf("state = %+v after Cancel, want not loading", state)
+	}
+	// Nobody waits for the request anymore, it is cancelled too
+	waitFor(t, "the end of the first request", func() bool {
+		cache.mutex.Lock()
+		defer cache.mutex.Unlock()
+		return len(cache.inflight) == 0
+	})
+
+	// The same key starts again after a Cancel
+	r.Load("a")
+	if state := stateOf(r); !state.Loading {
+		t.Fatalf("state = %+v, want loading again", state)
+	}
+	waitFor(t, "the second request", func() bool { return calls.Load() == 2 })
+
+	close(release)
+	waitFor(t, "the data", func() bool { return stateOf(r).HasData })
+	if state := stateOf(r); state.Data != "data of a" || state.Loading || state.Err != nil {
+		t.Errorf("state = %+v, want the data of a", state)
+	}
+}
+
+func TestResourceConcurrentLoads(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	r := newTestResource(blockingLoader(&calls, release), NewCache(0))
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.Load("k")
+		}()
+	}
+	wg.Wait()
+
+	// The first Load starts the request, the others find it loading
+	r.mutex.Lock()
+	generation := r.generation
+	r.mutex.Unlock()
+	if generation != 1 {
+		t.Errorf("%d requests started, want 1", generation)
+	}
+
+	close(release)
+	waitFor(t, "the data", func() bool { return stateOf(r).HasData })
+	r.Refresh()
+	waitFor(t, "the refresh", func() bool { return !stateOf(r).Loading })
+	if state := stateOf(r); state.Key != "k" || state.Data != "data of k" || calls.Load() != 2 {
+		t.Errorf("state = %+v after %d calls, want the data of k refreshed", state, calls.Load())
+	}
+}
+
+func TestResourceDropsStaleResponses(t *testing.T) {
+	releaseA := make(chan struct{})
+	cache := NewCache(0)
+	r := newTestResource(func(ctx context.Context, key string) (string, error) {
+		if key == "a" {
+			<-releaseA // Slow, and ignoring its context
+		}
+		return "data of " + key, nil
+	}, cache)
+
+	r.Load("a")
+	r.Load("b")
+	waitFor(t, "the data of b", func() bool { return stateOf(r).HasData })
+
+	close(releaseA)
+	waitFor(t, "the response of a", func() bool {
+		_, ok := cache.get(r.cacheKey("a"))
+		return ok
+	})
+	time.Sleep(10 * time.Millisecond)
+
+	if state := stateOf(r); state.Key != "b" || state.Data != "data of b" {
+		t.Errorf("state = %+v, want the data of b kept", state)
+	}
+}
+
+func TestResourceSharesRunningRequests(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	cache := NewCache(0)
+	loader := blockingLoader(&calls, release)
+	first := newTestResource(loader, cache)
+	second := newTestResource(loader, cache)
+
+	first.Load("k")
+	second.Load("k")
+	waitFor(t, "the request", func() bool { return calls.Load() == 1 })
+	close(release)
+	waitFor(t, "the data", func() bool { return stateOf(first).HasData && stateOf(second).HasData })
+
+	if calls.Load() != 1 {
+		t.Errorf("loader called %d times, want 1", calls.Load())
+	}
+
+	// Cached: rendered at once, without a request
+	third := newTestResource(loader, cache)
+	third.Load("k")
+	if state := stateOf(third); !state.HasData || state.Loading || state.Data != "data of k" {
+		t.Errorf("state = %+v, want the cached data", state)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("loader called %d times, want 1", calls.Load())
+	}
+}
+
+func TestResourceCacheKeepsTypesApart(t *testing.T) {
+	cache := NewCache(0)
+	text := newTestResource(func(ctx context.Context, key string) (string, error) {
+		return "text", nil
+	}, cache)
+	number := newTestResource(func(ctx context.Context, key string) (int, error) {
+		return 42, nil
+	}, cache)
+
+	text.Load("/api/item")
+	waitFor(t, "the text", func() bool { return stateOf(text).HasData })
+	number.Load("/api/item")
+	waitFor(t, "the number", func() bool { return stateOf(number).HasData })
+
+	if state := stateOf(number); state.Data != 42 || state.Err != nil {
+		t.Errorf("state = %+v, want 42", state)
+	}
+
+	cache.Invalidate("/api/item")
+	if _, ok := cache.get(text.cacheKey("/api/item")); ok {
+		t.Error("the text is still cached after Invalidate")
+	}
+	if _, ok := cache.get(number.cacheKey("/api/item")); ok {
+		t.Error("the number is still cached after Invalidate")
+	}
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	policy := RetryPolicy{Attempts: 5, Delay: 100 * time.Millisecond, MaxDelay: 500 * time.Millisecond}
+	want := []time.Duration{100, 200, 400, 500, 500}
+	for attempt, delay := range want {
+		if got := policy.delay(attempt); got != delay*time.Millisecond {
+			t.Errorf("delay(%d) = %v, want %v", attempt, got, delay*time.Millisecond)
+		}
+	}
+	// The shift overflows, the delay stays bounded
+	if got := policy.delay(80); got != policy.MaxDelay {
+		t.Errorf("delay(80) = %v, want %v", got, policy.MaxDelay)
+	}
+}
+
+func TestResourceRetries(t *testing.T) {
+	failure := errors.New("unavailable")
+	var calls []time.Time
+	r := newTestResource(func(ctx context.Context, key string) (string, error) {
+		calls = append(calls, time.Now())
+		if len(calls) < 3 {
+			return "", failure
+		}
+		return "data", nil
+	}, NewCache(0))
+	r.Retry = RetryPolicy{Attempts: 3, Delay: 5 * time.Millisecond}
+
+	r.Load("k")
+	waitFor(t, "the data", func() bool { return stateOf(r).HasData })
+
+	if len(calls) != 3 {
+		t.Fatalf("loader called %d times, want 3", len(calls))
+	}
+	for i, min := range []time.Duration{5 * time.Millisecond, 10 * time.Millisecond} {
+		if gap := calls[i+1].Sub(calls[i]); gap < min {
+			t.Errorf("retry %d after %v, want at least %v", i+1, gap, min)
+		}
+	}
+	if state := stateOf(r); state.Err != nil {
+		t.Errorf("state = %+v, want no error", state)
+	}
+}
+
+func TestResourceGivesUpAfterAttempts(t *testing.T) {
+	failure := errors.New("unavailable")
+	var calls atomic.Int32
+	r := newTestResource(func(ctx context.Context, key string) (string, error) {
+		calls.Add(1)
+		return "", failure
+	}, NewCache(0))
+	r.Retry = RetryPolicy{Attempts: 2, Delay: time.Millisecond}
+
+	r.Load("k")
+	waitFor(t, "the error", func() bool { return stateOf(r).Err != nil })
+
+	if state := stateOf(r); !errors.Is(state.Err, failure) || state.Loading || state.HasData {
+		t.Errorf("state = %+v, want the error", state)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("loader called %d times, want 3", calls.Load())
+	}
+}
@@ -1 +1,91 @@
+//go:build js && wasm
+
 package async
+
+import (
+	"syscall/js"
+	"time"
+)
+
+// Timer is a function scheduled on the event loop with SetTimeout or SetInterval
+// Its js.Func is released once it cannot run anymore: after a timeout ran, or once stopped
+//
+// The function runs on the event loop: a blocking call such as Await must be started in a goroutine.
+type Timer struct {
+	id       js.Value
+	fn       js.Func
+	interval bool
+	stopped  bool
+}
+
+// SetTimeout runs fn once after the delay
+//
+// Usage examples :
+//
+//	timer := async.SetTimeout(2*time.Second, func() { toast.Hide() })
+//	timer.Stop() // Cancelled if it has not run yet
+func SetTimeout(delay time.Duration, fn func()) *Timer {
+	t := &Timer{}
+	t.fn = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		t.release()
+		fn()
+		return nil
+	})
+	t.id = js.Global().Call("setTimeout", t.fn, delay.Milliseconds())
+	return t
+}
+
+// SetInterval runs fn every interval, until the timer is stopped
+func SetInterval(interval time.Duration, fn func()) *Timer {
+	t := &Timer{interval: true}
+	t.fn = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		fn()
+		return nil
+	})
+	t.id = js.Global().Call("setInterval", t.fn, interval.Milliseconds())
+	return t
+}
+
+// Stop cancels the timer and releases its function
+// Stopping a timer already stopped, or a timeout which already ran, does nothing
+func (t *Timer) Stop() {
+	if t.stopped {
+		return
+	}
+	if t.interval {
+		js.Global().Call("clearInterval", t.id)
+	} else {
+		js.Global().Call("clearTimeout", t.id)
+	}
+	t.release()
+}
+
+func (t *Timer) release() {
+	if t.stopped {
+		return
+	}
+	t.stopped = true
+	t.fn.Release()
+}
+
+// Debounce returns a function running fn once the calls have stopped for the delay,
+// such as a search sent when the user stops typing, and the function cancelling the pending run
+//
+// Usage examples :
+//
+//	search, cancel := async.Debounce(300*time.Millisecond, func() { go runSearch() })
+//	input.On("input", func(event vdom.Event) { search() })
+func Debounce(delay time.Duration, fn func()) (call func(), cancel func()) {
+	return debounce(scheduleTimeout, delay, fn)
+}
+
+// Throttle returns a function running fn at most once per interval, such as a scroll handler
+// The first call runs at once. The calls made during the interval are merged into one run at its end
+func Throttle(interval time.Duration, fn func()) func() {
+	return throttle(scheduleTimeout, interval, fn)
+}
+
+// scheduleTimeout schedules fn with SetTimeout
+func scheduleTimeout(delay time.Duration, fn func()) (stop func()) {
+	return SetTimeout(delay, fn).Stop
+}
@@ -0,0 +1,54 @@
+package async
+
+import "time"
+
+// schedule runs fn once after the delay and returns the function cancelling it
+// The timers use SetTimeout in the browser, the tests a fake clock
+type schedule func(delay time.Duration, fn func()) (stop func())
+
+// debounce is Debounce, with the timeouts of the schedule
+func debounce(after schedule, delay time.Duration, fn func()) (call func(), cancel func()) {
+	var stop func()
+	cancel = func() {
+		if stop != nil {
+			stop()
+			stop = nil
+		}
+	}
+	call = func() {
+		cancel()
+		stop = after(delay, func() {
+			stop = nil
+			fn()
+		})
+	}
+	return call, cancel
+}
+
+// throttle is Throttle, with the timeouts of the schedule
+func throttle(after schedule, interval time.Duration, fn func()) func() {
+	cooling := false
+	trailing := false
+
+	var start func()
+	start = func() {
+		cooling = true
+		after(interval, func() {
+			cooling = false
+			if trailing {
+				trailing = false
+				fn()
+				start()
+			}
+		})
+	}
+
+	return func() {
+		if cooling {
+			trailing = true
+			return
+		}
+		fn()
+		start()
+	}
+}
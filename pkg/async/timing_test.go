@@ -0,0 +1,118 @@
+package async
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// fakeClock is a schedule running the timeouts when the time is advanced
+type fakeClock struct {
+	now    time.Duration
+	timers []*fakeTimer
+}
+
+type fakeTimer struct {
+	due  time.Duration
+	fn   func()
+	done bool
+}
+
+func (c *fakeClock) after(delay time.Duration, fn func()) func() {
+	timer := &fakeTimer{due: c.now + delay, fn: fn}
+	c.timers = append(c.timers, timer)
+	return func() { timer.done = true }
+}
+
+// advance moves the time forward, running the timeouts due in the order of their time
+func (c *fakeClock) advance(d time.Duration) {
+	end := c.now + d
+	for {
+		var next *fakeTimer
+		for _, timer := range c.timers {
+			if !timer.done && timer.due <= end && (next == nil || timer.due < next.due) {
+				next = timer
+			}
+		}
+		if next == nil {
+			break
+		}
+		c.now = next.due
+		next.done = true
+		next.fn()
+	}
+	c.now = end
+}
+
+// pending returns the number of timeouts waiting to run
+func (c *fakeClock) pending() int {
+	count := 0
+	for _, timer := range c.timers {
+		if !timer.done {
+			count++
+		}
+	}
+	return count
+}
+
+func TestDebounce(t *testing.T) {
+	clock := &fakeClock{}
+	var runs []time.Duration
+	call, cancel := debounce(clock.after, 300*time.Millisecond, func() { runs = append(runs, clock.now) })
+
+	// Each call postpones the run
+	call()
+	clock.advance(100 * time.Millisecond)
+	call()
+	clock.advance(100 * time.Millisecond)
+	call()
+	clock.advance(250 * time.Millisecond)
+	if len(runs) != 0 {
+		t.Fatalf("ran at %v while the calls went on", runs)
+	}
+	clock.advance(50 * time.Millisecond)
+	if want := []time.Duration{500 * time.Millisecond}; !reflect.DeepEqual(runs, want) {
+		t.Fatalf("ran at %v, want %v", runs, want)
+	}
+
+	// Cancelled before the delay
+	call()
+	clock.advance(100 * time.Millisecond)
+	cancel()
+	clock.advance(time.Second)
+	if len(runs) != 1 || clock.pending() != 0 {
+		t.Errorf("ran at %v with %d timeouts pending after cancel", runs, clock.pending())
+	}
+	cancel() // Nothing pending anymore
+}
+
+func TestThrottle(t *testing.T) {
+	clock := &fakeClock{}
+	var runs []time.Duration
+	call := throttle(clock.after, 100*time.Millisecond, func() { runs = append(runs, clock.now) })
+
+	steps := []struct {
+		at    time.Duration // Time of the call
+		calls int
+	}{
+		{0, 1},   // Runs at once
+		{10, 2},  // Merged into one run at the end of the interval, at 100
+		{150, 1}, // During the interval started by the run at 100: runs at 200
+		{350, 1}, // The interval ended at 300 without calls: runs at once
+	}
+	for _, step := range steps {
+		clock.advance(step.at*time.Millisecond - clock.now)
+		for range step.calls {
+			call()
+		}
+	}
+	clock.advance(time.Second)
+
+	want := []time.Duration{0, 100 * time.Millisecond, 200 * time.Millisecond, 350 * time.Millisecond}
+	if !reflect.DeepEqual(runs, want) {
+		t.Errorf("ran at %v, want %v", runs, want)
+	}
+	if clock.pending() != 0 {
+		t.Errorf("%d timeouts pending once the calls stopped", clock.pending())
+	}
+}
@@ -1,5 +1,3 @@
-//go:build js && wasm
-
 package component
 
 import (
@@ -24,6 +22,6 @@ func NewContainer() *Container {
 
 func (c *Container) AddChild(child vdom.Component) *Container {
 	c.Children = append(c.Children, child)
-	c.ComponentBase.AddChildren(child.Render())
+	c.ComponentBase.AddChild(child)
 	return c
 }
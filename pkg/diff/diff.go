@@ -0,0 +1,326 @@
+// Package diff provides the platform independent reconciliation of Vortex virtual DOM trees.
+//
+// The diff compares two vdom.VNode trees and returns the list of operations needed
+// to turn the first one into the second one. It never touches the DOM itself:
+// a backend (see the renderer package for the browser) applies the operations in order.
+//
+// Basic Usage:
+//
+//	ops := diff.Diff(oldTree, newTree)
+//	for _, op := range ops {
+//	    // apply op to the target
+//	}
+//
+// The diff hands over the Element reference of every reused node from the old tree
+// to the new tree, so the operations can always be resolved against the new tree.
+//
+// Component nodes (vdom.VNodeComponent) have no DOM of their own: the diff renders them
+// and works on the subtree they rendered. Backends resolve them with vdom.VNode.Host.
+// Fragments (vdom.VNodeFragment) can be the Parent of an operation: their children belong
+// to the DOM parent of the fragment, and a nil Anchor means before the closing marker.
+// Portals (vdom.VNodePortal) can be the Parent of an operation too: their children belong to their target.
+package diff
+
+import (
+	"reflect"
+
+	"github.com/AureClai/vortex/pkg/vdom"
+)
+
+// OpKind is the type of a patch operation
+type OpKind int
+
+const (
+	// OpCreate creates Node (and its whole subtree) and inserts it in Parent before Anchor
+	OpCreate OpKind = iota
+	// OpRemove removes Node from Parent
+	OpRemove
+	// OpReplace creates Node and puts it in place of Old in Parent
+	OpReplace
+	// OpSetAttribute sets the prop Name of Node to Value
+	// The backend writes it as an attribute or a DOM property depending on vdom.PropKindOf
+	OpSetAttribute
+	// OpRemoveAttribute removes the prop Name of Node, Value holds its previous value
+	OpRemoveAttribute
+	// OpSetStyle switches the applied style of Node from the class of Old (Old.StyleClass) to its own
+	// It is emitted when the style changed, or when the same style was updated since
+	OpSetStyle
+	// OpMove moves the already existing Node in Parent before Anchor
+	OpMove
+	// OpSetText sets the content of the text Node to Node.Text
+	OpSetText
+	// OpSetListener binds Handler to the event Name of Node, replacing the previous handler
+	OpSetListener
+	// OpRemoveListener removes the listener of the event Name of Node
+	OpRemoveListener
+	// OpUpdateComponent notifies that the subtree of the component Node has been patched
+	// It comes after all the operations of the subtree. Old is the previous component node:
+	// when it held another instance (see vdom.SameInstance), that instance has been replaced
+	OpUpdateComponent
+	// OpBind hands the bound parts of Old over to Node (see vdom.Binding)
+	// The backend keeps following the bindings Node shares with Old, and switches the others
+	OpBind
+)
+
+// String returns a readable name of the operation kind
+func (k OpKind) String() string {
+	switch k {
+	case OpCreate:
+		return "create"
+	case OpRemove:
+		return "remove"
+	case OpReplace:
+		return "replace"
+	case OpSetAttribute:
+		return "set-attribute"
+	case OpRemoveAttribute:
+		return "remove-attribute"
+	case OpSetStyle:
+		return "set-style"
+	case OpMove:
+		return "move"
+	case OpSetText:
+		return "set-text"
+	case OpSetListener:
+		return "set-listener"
+	case OpRemoveListener:
+		return "remove-listener"
+	case OpUpdateComponent:
+		return "update-component"
+	case OpBind:
+		return "bind"
+	}
+	return "unknown"
+}
+
+// Op is a single patch operation
+// Only the fields relevant for its Kind are set
+type Op struct {
+	Kind   OpKind
+	Parent *vdom.VNode // Parent node, nil when the node is the root of the diff, may be a fragment
+	Node   *vdom.VNode // Node the operation applies to (always from the new tree, except for OpRemove), may be a component node
+	Old    *vdom.VNode // Previous node for OpReplace, OpSetStyle, OpUpdateComponent and OpBind
+	Anchor *vdom.VNode // Sibling before which the node is inserted, nil to append
+	Name   string      // Attribute or event name
+	Value  interface{} // Attribute value (previous value for OpRemoveAttribute)
+
+	Handler func(event vdom.Event) // Event handler for OpSetListener
+}
+
+// Diff compares the old and the new tree and returns the operations to apply, in order
+// A nil old tree creates the new one, a nil new tree removes the old one
+// The component nodes of the new tree are rendered along the way
+func Diff(oldVNode, newVNode *vdom.VNode) []Op {
+	d := &differ{}
+	d.diffNode(nil, oldVNode, newVNode)
+	return d.ops
+}
+
+// Rerender renders again the component of a mounted component node
+// and returns the operations patching the subtree it rendered previously
+// The operations without parent apply to the DOM parent of the component
+func Rerender(node *vdom.VNode) []Op {
+	d := &differ{}
+	oldRendered := node.Rendered
+	d.diffNode(nil, oldRendered, node.RenderComponent())
+	d.emit(Op{Kind: OpUpdateComponent, Node: node, Old: node})
+	return d.ops
+}
+
+// differ accumulates the operations while walking the trees
+type differ struct {
+	ops []Op
+}
+
+func (d *differ) emit(op Op) {
+	d.ops = append(d.ops, op)
+}
+
+// diffNode compares two nodes occupying the same place under parent
+func (d *differ) diffNode(parent, oldVNode, newVNode *vdom.VNode) {
+	switch {
+	case oldVNode == nil && newVNode == nil:
+		return
+
+	// Creation
+	case oldVNode == nil:
+		expand(newVNode)
+		d.emit(Op{Kind: OpCreate, Parent: parent, Node: newVNode})
+		return
+
+	// Removal
+	case newVNode == nil:
+		d.emit(Op{Kind: OpRemove, Parent: parent, Node: oldVNode})
+		return
+
+	// Same node, nothing can have changed
+	// A component node is still rendered again: its component may render something else
+	case oldVNode == newVNode && newVNode.Type != vdom.VNodeComponent:
+		return
+
+	// Replacement
+	case oldVNode.Type != newVNode.Type || oldVNode.Tag != newVNode.Tag || oldVNode.Target != newVNode.Target,
+		newVNode.Type == vdom.VNodeComponent && !vdom.SameComponent(oldVNode, newVNode):
+		expand(newVNode)
+		d.emit(Op{Kind: OpReplace, Parent: parent, Node: newVNode, Old: oldVNode})
+		return
+
+	// Component nodes are transparent: their subtrees are diffed in place
+	case newVNode.Type == vdom.VNodeComponent:
+		d.diffComponent(parent, oldVNode, newVNode)
+		return
+	}
+
+	// Both nodes are of the same type: the new one takes over the DOM reference
+	newVNode.Element = oldVNode.Element
+
+	switch newVNode.Type {
+	case vdom.VNodeText:
+		if oldVNode.Text != newVNode.Text {
+			d.emit(Op{Kind: OpSetText, Parent: parent, Node: newVNode})
+		}
+		d.diffBindings(parent, oldVNode, newVNode)
+		return
+
+	case vdom.VNodeFragment:
+		// A fragment only has children, which live in the DOM parent between its markers
+		newVNode.EndElement = oldVNode.EndElement
+		d.diffChildren(newVNode, oldVNode.Children, newVNode.Children)
+		return
+
+	case vdom.VNodePortal:
+		// The placeholder stays in the parent, the children live in the target
+		d.diffChildren(newVNode, oldVNode.Children, newVNode.Children)
+		return
+	}
+
+	d.diffProps(newVNode, oldVNode.Props, newVNode.Props)
+	d.diffListeners(newVNode, oldVNode.EventHandlers, newVNode.EventHandlers)
+	if styleChanged(oldVNode, newVNode) {
+		d.emit(Op{Kind: OpSetStyle, Parent: parent, Node: newVNode, Old: oldVNode})
+	} else {
+		newVNode.StyleClass = oldVNode.StyleClass
+	}
+	d.diffBindings(parent, oldVNode, newVNode)
+	d.diffChildren(newVNode, oldVNode.Children, newVNode.Children)
+}
+
+// diffBindings hands the bindings over to the new node when either node has some
+// It comes after the props and the style, so the backend binds a node already up to date
+func (d *differ) diffBindings(parent, oldVNode, newVNode *vdom.VNode) {
+	if oldVNode.HasBindings() || newVNode.HasBindings() {
+		d.emit(Op{Kind: OpBind, Parent: parent, Node: newVNode, Old: oldVNode})
+	}
+}
+
+// diffComponent renders the new component node and diffs its subtree against the previous one
+// A stateful instance is kept from the old node, so its state survives the render of its parent
+func (d *differ) diffComponent(parent, oldVNode, newVNode *vdom.VNode) {
+	if vdom.IsStateful(oldVNode.Component) {
+		newVNode.Component = oldVNode.Component
+	}
+	// Read before rendering: both nodes may be the same one
+	oldRendered := oldVNode.Rendered
+	d.diffNode(parent, oldRendered, newVNode.RenderComponent())
+	d.emit(Op{Kind: OpUpdateComponent, Parent: parent, Node: newVNode, Old: oldVNode})
+}
+
+// expand renders every component node of a subtree about to be created
+func expand(node *vdom.VNode) {
+	if node == nil {
+		return
+	}
+	if node.Type == vdom.VNodeComponent {
+		expand(node.RenderComponent())
+		return
+	}
+	for _, child := range node.Children {
+		expand(child)
+	}
+}
+
+// styleChanged reports whether the element must switch to another style class
+// The same *style.Style may have been updated since the old node was applied: its class name changed
+func styleChanged(oldVNode, newVNode *vdom.VNode) bool {
+	if oldVNode.AppliedStyle != newVNode.AppliedStyle {
+		return true
+	}
+	return newVNode.AppliedStyle != nil && newVNode.AppliedStyle.GetClassName() != oldVNode.StyleClass
+}
+
+// diffProps emits the attribute operations between the old and the new props of node
+func (d *differ) diffProps(node *vdom.VNode, oldProps, newProps map[string]interface{}) {
+	// Remove the props that no longer exist
+	for name := range oldProps {
+		if _, exists := newProps[name]; !exists {
+			d.emit(Op{Kind: OpRemoveAttribute, Node: node, Name: name, Value: oldProps[name]})
+		}
+	}
+
+	// Add or update the others, only when the value changed
+	// DOM properties are always handed over: the user may have changed them (controlled inputs)
+	for name, newValue := range newProps {
+		oldValue, exists := oldProps[name]
+		if !exists || !sameProp(oldValue, newValue) || vdom.PropKindOf(name, newValue) == vdom.PropProperty {
+			d.emit(Op{Kind: OpSetAttribute, Node: node, Name: name, Value: newValue})
+		}
+	}
+}
+
+// sameProp reports whether two prop values are equal
+// Slices, maps, and the structs and arrays which may hold them, are compared deeply:
+// == panics on them
+func sameProp(a, b interface{}) bool {
+	t := reflect.TypeOf(a)
+	if t != reflect.TypeOf(b) {
+		return false
+	}
+	switch {
+	case t == nil:
+		return true
+	case !t.Comparable(), t.Kind() == reflect.Struct, t.Kind() == reflect.Array:
+		return reflect.DeepEqual(a, b)
+	}
+	return a == b
+}
+
+// diffListeners emits the listener operations between the old and the new handlers of node
+// Go functions cannot be compared: every handler of the new node is handed over to the backend,
+// which is expected to swap it cheaply without touching the DOM listener
+func (d *differ) diffListeners(node *vdom.VNode, oldHandlers, newHandlers map[string]func(event vdom.Event)) {
+	for event := range oldHandlers {
+		if _, exists := newHandlers[event]; !exists {
+			d.emit(Op{Kind: OpRemoveListener, Node: node, Name: event})
+		}
+	}
+
+	for event, handler := range newHandlers {
+		d.emit(Op{Kind: OpSetListener, Node: node, Name: event, Handler: handler})
+	}
+}
+
+// diffChildren reconciles the children of parent
+// As soon as a child carries a key the keyed algorithm is used (see keyed.go),
+// otherwise the children are simply compared index by index
+func (d *differ) diffChildren(parent *vdom.VNode, oldChildren, newChildren []*vdom.VNode) {
+	if hasKeys(oldChildren) || hasKeys(newChildren) {
+		d.diffKeyedChildren(parent, oldChildren, newChildren)
+		return
+	}
+
+	maxLen := len(oldChildren)
+	if len(newChildren) > maxLen {
+		maxLen = len(newChildren)
+	}
+
+	for i := 0; i < maxLen; i++ {
+		var oldChild, newChild *vdom.VNode
+		if i < len(oldChildren) {
+			oldChild = oldChildren[i]
+		}
+		if i < len(newChildren) {
+			newChild = newChildren[i]
+		}
+		d.diffNode(parent, oldChild, newChild)
+	}
+}
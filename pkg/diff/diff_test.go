@@ -0,0 +1,372 @@
+//go:build !(js && wasm)
+
+package diff
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/AureClai/vortex/pkg/style"
+	"github.com/AureClai/vortex/pkg/vdom"
+)
+
+func element(tag string, children ...*vdom.VNode) *vdom.VNode {
+	return &vdom.VNode{Type: vdom.VNodeElement, Tag: tag, Props: map[string]interface{}{}, Children: children}
+}
+
+func keyed(key string) *vdom.VNode {
+	node := element("li")
+	node.Key = key
+	return node
+}
+
+func text(content string) *vdom.VNode {
+	return &vdom.VNode{Type: vdom.VNodeText, Text: content}
+}
+
+func list(keys ...string) *vdom.VNode {
+	children := make([]*vdom.VNode, len(keys))
+	for i, key := range keys {
+		children[i] = keyed(key)
+	}
+	return element("ul", children...)
+}
+
+// kinds returns the kinds of the operations, in order
+func kinds(ops []Op) []OpKind {
+	result := make([]OpKind, len(ops))
+	for i, op := range ops {
+		result[i] = op.Kind
+	}
+	return result
+}
+
+// opsOf returns the operations of the kind
+func opsOf(ops []Op, kind OpKind) []Op {
+	result := []Op{}
+	for _, op := range ops {
+		if op.Kind == kind {
+			result = append(result, op)
+		}
+	}
+	return result
+}
+
+func TestDiffCreatesAndRemovesTheRoot(t *testing.T) {
+	tree := element("div")
+	if got := kinds(Diff(nil, tree)); !reflect.DeepEqual(got, []OpKind{OpCreate}) {
+		t.Errorf("create: got %v", got)
+	}
+	if got := kinds(Diff(tree, nil)); !reflect.DeepEqual(got, []OpKind{OpRemove}) {
+		t.Errorf("remove: got %v", got)
+	}
+}
+
+func TestDiffReplacesOnTagChange(t *testing.T) {
+	oldTree := element("div", element("span"))
+	newTree := element("div", element("p"))
+	ops := Diff(oldTree, newTree)
+
+	replaced := opsOf(ops, OpReplace)
+	if len(replaced) != 1 {
+		t.Fatalf("got %v, want one replace", kinds(ops))
+	}
+	if replaced[0].Old != oldTree.Children[0] || replaced[0].Node != newTree.Children[0] || replaced[0].Parent != newTree {
+		t.Errorf("replace op does not reference the old and the new child under the new parent")
+	}
+}
+
+func TestDiffKeepsElementAndSetsText(t *testing.T) {
+	oldTree := element("div", text("a"))
+	oldTree.Element = "div-element"
+	oldTree.Children[0].Element = "text-element"
+	newTree := element("div", text("b"))
+
+	ops := Diff(oldTree, newTree)
+	if got := kinds(ops); !reflect.DeepEqual(got, []OpKind{OpSetText}) {
+		t.Fatalf("got %v, want set-text", got)
+	}
+	if newTree.Element != "div-element" || newTree.Children[0].Element != "text-element" {
+		t.Errorf("the DOM references are not handed over to the new tree")
+	}
+}
+
+func TestDiffProps(t *testing.T) {
+	oldTree := element("input")
+	oldTree.Props = map[string]interface{}{"id": "a", "title": "same", "placeholder": "gone", "value": "x"}
+	newTree := element("input")
+	newTree.Props = map[string]interface{}{"id": "b", "title": "same", "class": "new", "value": "x"}
+
+	ops := Diff(oldTree, newTree)
+
+	set := map[string]interface{}{}
+	for _, op := range opsOf(ops, OpSetAttribute) {
+		set[op.Name] = op.Value
+	}
+	// The DOM property value is always handed over, the unchanged title is not
+	want := map[string]interface{}{"id": "b", "class": "new", "value": "x"}
+	if !reflect.DeepEqual(set, want) {
+		t.Errorf("set attributes: got %v, want %v", set, want)
+	}
+
+	removed := opsOf(ops, OpRemoveAttribute)
+	if len(removed) != 1 || removed[0].Name != "placeholder" || removed[0].Value != "gone" {
+		t.Errorf("remove attributes: got %+v", removed)
+	}
+}
+
+func TestDiffPropsNotComparable(t *testing.T) {
+	oldTree := element("div")
+	oldTree.Props = map[string]interface{}{"data-list": []string{"a"}, "data-map": map[string]int{"a": 1}}
+	newTree := element("div")
+	newTree.Props = map[string]interface{}{"data-list": []string{"a"}, "data-map": map[string]int{"a": 2}}
+
+	ops := Diff(oldTree, newTree)
+	set := opsOf(ops, OpSetAttribute)
+	if len(set) != 1 || set[0].Name != "data-map" {
+		t.Errorf("got %+v, want only data-map to be set", set)
+	}
+}
+
+func TestDiffStyle(t *testing.T) {
+	red := style.New(style.Color(style.RGB(255, 0, 0)))
+	blue := style.New(style.Color(style.RGB(0, 0, 255)))
+
+	oldTree := element("div")
+	oldTree.AppliedStyle = red
+	oldTree.StyleClass = red.GetClassName()
+
+	same := element("div")
+	same.AppliedStyle = red
+	if ops := Diff(oldTree, same); len(ops) != 0 {
+		t.Errorf("same style: got %v, want no operation", kinds(ops))
+	}
+	if same.StyleClass != red.GetClassName() {
+		t.Errorf("same style: the class is not handed over")
+	}
+
+	changed := element("div")
+	changed.AppliedStyle = blue
+	ops := Diff(oldTree, changed)
+	if got := kinds(ops); !reflect.DeepEqual(got, []OpKind{OpSetStyle}) {
+		t.Fatalf("changed style: got %v, want set-style", got)
+	}
+	if ops[0].Old != oldTree || ops[0].Node != changed {
+		t.Errorf("changed style: the op does not reference the old and the new node")
+	}
+}
+
+func TestDiffBindings(t *testing.T) {
+	title := vdom.Bind(func() string { return "a" })
+	bound := func(binding *vdom.Binding) *vdom.VNode {
+		node := element("p")
+		node.Props["title"] = binding
+		return node
+	}
+
+	oldTree := bound(title)
+	if ops := opsOf(Diff(element("div"), element("div")), OpBind); len(ops) != 0 {
+		t.Errorf("no binding: got %+v, want no bind", ops)
+	}
+
+	// Kept: handed over to the new node, the backend keeps following it (see vdom.BindingChanges)
+	kept := bound(title)
+	ops := opsOf(Diff(oldTree, kept), OpBind)
+	if len(ops) != 1 || ops[0].Old != oldTree || ops[0].Node != kept {
+		t.Fatalf("kept binding: got %+v, want a bind from the old node to the new one", ops)
+	}
+	if stopped, started := vdom.BindingChanges(oldTree.Bindings(), kept.Bindings()); stopped != nil || started != nil {
+		t.Errorf("kept binding: stopped %v, started %v, want nothing switched", stopped, started)
+	}
+
+	swapped := bound(vdom.Bind(func() string { return "b" }))
+	ops = opsOf(Diff(kept, swapped), OpBind)
+	if len(ops) != 1 || ops[0].Old != kept || ops[0].Node != swapped {
+		t.Fatalf("swapped binding: got %+v, want a bind from the old node to the new one", ops)
+	}
+	if stopped, started := vdom.BindingChanges(kept.Bindings(), swapped.Bindings()); len(stopped) != 1 || len(started) != 1 {
+		t.Errorf("swapped binding: stopped %v, started %v, want the title switched", stopped, started)
+	}
+
+	// Unbound: the last bind lets the backend stop following the old binding
+	ops = opsOf(Diff(swapped, element("p")), OpBind)
+	if len(ops) != 1 {
+		t.Errorf("removed binding: got %+v, want a bind", ops)
+	}
+}
+
+func TestDiffKeyedMoves(t *testing.T) {
+	tests := []struct {
+		name       string
+		oldKeys    []string
+		newKeys    []string
+		wantMoves  []string
+		wantCreate []string
+		wantRemove []string
+	}{
+		{name: "unchanged", oldKeys: []string{"a", "b", "c"}, newKeys: []string{"a", "b", "c"}},
+		{name: "append", oldKeys: []string{"a", "b"}, newKeys: []string{"a", "b", "c"}, wantCreate: []string{"c"}},
+		{name: "remove middle", oldKeys: []string{"a", "b", "c"}, newKeys: []string{"a", "c"}, wantRemove: []string{"b"}},
+		{name: "swap", oldKeys: []string{"a", "b", "c", "d"}, newKeys: []string{"a", "c", "b", "d"}, wantMoves: []string{"c"}},
+		{name: "last to first", oldKeys: []string{"a", "b", "c", "d"}, newKeys: []string{"d", "a", "b", "c"}, wantMoves: []string{"d"}},
+		{name: "reverse", oldKeys: []string{"a", "b", "c", "d"}, newKeys: []string{"d", "c", "b", "a"}, wantMoves: []string{"b", "c", "d"}},
+		{
+			name:    "moves, creations and removals",
+			oldKeys: []string{"a", "b", "c", "d", "e", "f"}, newKeys: []string{"a", "e", "c", "x", "d", "b"},
+			wantMoves: []string{"b", "e"}, wantCreate: []string{"x"}, wantRemove: []string{"f"},
+		},
+	}
+
+	keysOf := func(ops []Op) []string {
+		keys := []string{}
+		for _, op := range ops {
+			keys = append(keys, op.Node.Key)
+		}
+		return keys
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldTree, newTree := list(tt.oldKeys...), list(tt.newKeys...)
+			ops := Diff(oldTree, newTree)
+
+			moves := keysOf(opsOf(ops, OpMove))
+			if tt.wantMoves == nil {
+				tt.wantMoves = []string{}
+			}
+			if !reflect.DeepEqual(moves, tt.wantMoves) {
+				t.Errorf("moves: got %v, want %v", moves, tt.wantMoves)
+			}
+			if got := keysOf(opsOf(ops, OpCreate)); len(got) != len(tt.wantCreate) || (len(got) > 0 && !reflect.DeepEqual(got, tt.wantCreate)) {
+				t.Errorf("creations: got %v, want %v", got, tt.wantCreate)
+			}
+			if got := keysOf(opsOf(ops, OpRemove)); len(got) != len(tt.wantRemove) || (len(got) > 0 && !reflect.DeepEqual(got, tt.wantRemove)) {
+				t.Errorf("removals: got %v, want %v", got, tt.wantRemove)
+			}
+
+			// Applying the moves and creations in order must give the new order
+			if got := applyOrder(tt.oldKeys, ops); !reflect.DeepEqual(got, tt.newKeys) {
+				t.Errorf("applied order: got %v, want %v", got, tt.newKeys)
+			}
+		})
+	}
+}
+
+func TestDiffKeyedSkipsNilChildren(t *testing.T) {
+	oldTree := element("ul", keyed("a"), nil, keyed("b"), keyed("c"))
+	newTree := element("ul", keyed("c"), nil, keyed("a"), nil, keyed("d"))
+
+	ops := Diff(oldTree, newTree)
+	for _, op := range ops {
+		if op.Node == nil && op.Kind != OpRemove {
+			t.Errorf("operation %v on a nil child", op.Kind)
+		}
+	}
+	if got := applyOrder([]string{"a", "b", "c"}, ops); !reflect.DeepEqual(got, []string{"c", "a", "d"}) {
+		t.Errorf("applied order: got %v, want [c a d]", got)
+	}
+}
+
+// applyOrder replays the operations on the list of keys, as a DOM backend would
+func applyOrder(keys []string, ops []Op) []string {
+	order := append([]string(nil), keys...)
+	indexOf := func(key string) int {
+		for i, k := range order {
+			if k == key {
+				return i
+			}
+		}
+		return -1
+	}
+	insert := func(key string, anchor *vdom.VNode) {
+		if i := indexOf(key); i >= 0 {
+			order = append(order[:i], order[i+1:]...)
+		}
+		at := len(order)
+		if anchor != nil {
+			at = indexOf(anchor.Key)
+		}
+		order = append(order[:at], append([]string{key}, order[at:]...)...)
+	}
+
+	for _, op := range ops {
+		switch op.Kind {
+		case OpCreate, OpMove:
+			insert(op.Node.Key, op.Anchor)
+		case OpRemove:
+			if i := indexOf(op.Node.Key); i >= 0 {
+				order = append(order[:i], order[i+1:]...)
+			}
+		}
+	}
+	return order
+}
+
+func TestLongestIncreasingSubsequence(t *testing.T) {
+	tests := []struct {
+		values []int
+		want   []int
+	}{
+		{values: []int{}, want: []int{}},
+		{values: []int{1, 2, 3}, want: []int{0, 1, 2}},
+		{values: []int{3, 2, 1}, want: []int{2}},
+		{values: []int{2, 0, 1, 3}, want: []int{2, 3}},
+		{values: []int{5, 1, 3, 0, 4, 2}, want: []int{1, 2, 4}},
+	}
+	for _, tt := range tests {
+		if got := longestIncreasingSubsequence(tt.values); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("lis(%v): got %v, want %v", tt.values, got, tt.want)
+		}
+	}
+}
+
+func TestDiffFragments(t *testing.T) {
+	oldFragment := vdom.NewFragment(text("a"), element("b"))
+	oldFragment.Element, oldFragment.EndElement = "start", "end"
+	oldTree := element("div", oldFragment)
+
+	newFragment := vdom.NewFragment(text("a"), element("b"), element("i"))
+	newTree := element("div", newFragment)
+
+	ops := Diff(oldTree, newTree)
+	if got := kinds(ops); !reflect.DeepEqual(got, []OpKind{OpCreate}) {
+		t.Fatalf("got %v, want one create", got)
+	}
+	if ops[0].Parent != newFragment || ops[0].Anchor != nil {
+		t.Errorf("the child is not appended to the fragment (before its closing marker)")
+	}
+	if newFragment.Element != "start" || newFragment.EndElement != "end" {
+		t.Errorf("the fragment markers are not handed over")
+	}
+
+	// A fragment replacing an element is a replacement
+	ops = Diff(element("div", element("p")), element("div", vdom.NewFragment(text("x"))))
+	if got := kinds(ops); !reflect.DeepEqual(got, []OpKind{OpReplace}) {
+		t.Errorf("got %v, want replace", got)
+	}
+}
+
+// label renders the internal node of its ComponentBase, changed in place between renders
+type label struct {
+	vdom.ComponentBase
+}
+
+func TestRerenderComponentChangedInPlace(t *testing.T) {
+	l := &label{ComponentBase: vdom.NewComponentBase("span")}
+	l.SetProp("title", "before")
+	node := vdom.NewComponentVNode(l, nil)
+	Diff(nil, node)
+
+	l.SetProp("title", "after")
+	ops := opsOf(Rerender(node), OpSetAttribute)
+	if len(ops) != 1 || ops[0].Name != "title" || ops[0].Value != "after" {
+		t.Errorf("got %+v, want title set to after", ops)
+	}
+
+	// The same component node in both trees is rendered again too
+	l.SetProp("title", "again")
+	ops = opsOf(Diff(element("div", node), element("div", node)), OpSetAttribute)
+	if len(ops) != 1 || ops[0].Value != "again" {
+		t.Errorf("same component node: got %+v, want title set to again", ops)
+	}
+}
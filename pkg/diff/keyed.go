@@ -0,0 +1,201 @@
+package diff
+
+import (
+	"slices"
+	"strconv"
+
+	"github.com/AureClai/vortex/pkg/vdom"
+)
+
+// hasKeys reports whether at least one child of the list carries a key
+func hasKeys(children []*vdom.VNode) bool {
+	for _, child := range children {
+		if child != nil && child.Key != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// childIdentity returns the identity used to match a child between two renders.
+// Keyed children are matched by key, children without key fall back to their index.
+func childIdentity(child *vdom.VNode, index int) string {
+	if child.Key != "" {
+		return "k:" + child.Key
+	}
+	return "i:" + strconv.Itoa(index)
+}
+
+// sameIdentity reports whether two children at the same relative position can be patched together
+func sameIdentity(oldChild *vdom.VNode, newChild *vdom.VNode) bool {
+	return oldChild.Key == newChild.Key
+}
+
+// diffKeyedChildren reconciles two lists of children using their keys.
+// Existing nodes are reused and moved instead of being rebuilt:
+//  1. the common prefix and suffix are patched in place
+//  2. the remaining old children are matched with the new ones by identity,
+//     unmatched old children are removed
+//  3. the longest increasing subsequence of the matched children stays in place,
+//     every other child is moved (or created) before its next sibling
+func (d *differ) diffKeyedChildren(parent *vdom.VNode, oldChildren, newChildren []*vdom.VNode) {
+	// The nil children render nothing, as in the unkeyed path
+	oldChildren = withoutNil(oldChildren)
+	newChildren = withoutNil(newChildren)
+
+	start := 0
+	oldEnd := len(oldChildren) - 1
+	newEnd := len(newChildren) - 1
+
+	// 1. Common prefix
+	for start <= oldEnd && start <= newEnd && sameIdentity(oldChildren[start], newChildren[start]) {
+		d.diffNode(parent, oldChildren[start], newChildren[start])
+		start++
+	}
+
+	// Common suffix
+	for start <= oldEnd && start <= newEnd && sameIdentity(oldChildren[oldEnd], newChildren[newEnd]) {
+		d.diffNode(parent, oldChildren[oldEnd], newChildren[newEnd])
+		oldEnd--
+		newEnd--
+	}
+
+	// Only insertions remain
+	if start > oldEnd {
+		anchor := anchorAt(newChildren, newEnd+1)
+		for i := start; i <= newEnd; i++ {
+			d.emit(Op{Kind: OpCreate, Parent: parent, Node: newChildren[i], Anchor: anchor})
+		}
+		return
+	}
+
+	// Only removals remain
+	if start > newEnd {
+		for i := start; i <= oldEnd; i++ {
+			d.diffNode(parent, oldChildren[i], nil)
+		}
+		return
+	}
+
+	// 2. Match the remaining children by identity
+	newIndexByIdentity := make(map[string]int, newEnd-start+1)
+	for i := start; i <= newEnd; i++ {
+		newIndexByIdentity[childIdentity(newChildren[i], i)] = i
+	}
+
+	// sources[i] holds the old index (+1) of the child now at start+i, 0 for a new child
+	sources := make([]int, newEnd-start+1)
+	moved := false
+	lastNewIndex := 0
+	for i := start; i <= oldEnd; i++ {
+		oldChild := oldChildren[i]
+		j, found := newIndexByIdentity[childIdentity(oldChild, i)]
+		if !found || sources[j-start] != 0 {
+			d.diffNode(parent, oldChild, nil)
+			continue
+		}
+
+		sources[j-start] = i + 1
+		if j < lastNewIndex {
+			moved = true
+		} else {
+			lastNewIndex = j
+		}
+		d.diffNode(parent, oldChild, newChildren[j])
+	}
+
+	// 3. Move and create, walking backwards so the anchor is always in place
+	var stable []int
+	if moved {
+		stable = longestIncreasingSubsequence(sources)
+	}
+	s := len(stable) - 1
+	for i := len(sources) - 1; i >= 0; i-- {
+		index := start + i
+		child := newChildren[index]
+		anchor := anchorAt(newChildren, index+1)
+
+		switch {
+		case sources[i] == 0:
+			d.emit(Op{Kind: OpCreate, Parent: parent, Node: child, Anchor: anchor})
+		case !moved:
+			// Nothing to move
+		case s >= 0 && stable[s] == i:
+			s--
+		default:
+			d.emit(Op{Kind: OpMove, Parent: parent, Node: child, Anchor: anchor})
+		}
+	}
+}
+
+// withoutNil returns the children which are not nil, the list itself when none is
+func withoutNil(children []*vdom.VNode) []*vdom.VNode {
+	if !slices.Contains(children, nil) {
+		return children
+	}
+	filtered := make([]*vdom.VNode, 0, len(children))
+	for _, child := range children {
+		if child != nil {
+			filtered = append(filtered, child)
+		}
+	}
+	return filtered
+}
+
+// anchorAt returns the child at the given index, or nil past the end
+func anchorAt(children []*vdom.VNode, index int) *vdom.VNode {
+	if index < len(children) {
+		return children[index]
+	}
+	return nil
+}
+
+// longestIncreasingSubsequence returns the positions of the longest strictly
+// increasing subsequence of the values, ignoring the zeros (new children)
+func longestIncreasingSubsequence(values []int) []int {
+	predecessors := make([]int, len(values))
+	tails := make([]int, 0, len(values)) // positions of the smallest tail for each length
+
+	for i, value := range values {
+		if value == 0 {
+			continue
+		}
+
+		if len(tails) == 0 || values[tails[len(tails)-1]] < value {
+			if len(tails) > 0 {
+				predecessors[i] = tails[len(tails)-1]
+			}
+			tails = append(tails, i)
+			continue
+		}
+
+		// Binary search for the first tail greater or equal to the value
+		low, high := 0, len(tails)-1
+		for low < high {
+			mid := (low + high) / 2
+			if values[tails[mid]] < value {
+				low = mid + 1
+			} else {
+				high = mid
+			}
+		}
+		if value < values[tails[low]] {
+			if low > 0 {
+				predecessors[i] = tails[low-1]
+			}
+			tails[low] = i
+		}
+	}
+
+	// Rebuild the sequence from the last tail
+	result := make([]int, len(tails))
+	if len(tails) == 0 {
+		return result
+	}
+	current := tails[len(tails)-1]
+	for i := len(tails) - 1; i >= 0; i-- {
+		result[i] = current
+		current = predecessors[current]
+	}
+	return result
+}
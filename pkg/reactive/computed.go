@@ -0,0 +1,96 @@
+package reactive
+
+// computedState tells whether a computed value must be checked or computed again
+type computedState int
+
+const (
+	computedInitial computedState = iota // Never computed
+	computedClean                        // Up to date
+	computedStale                        // A source may have changed, to be checked on the next read
+)
+
+// Computed is a value derived from signals and other computed values
+// It is computed lazily, when read, and only again once one of the values it read changed.
+type Computed[T any] struct {
+	fn        func() T
+	equal     func(a, b T) bool
+	value     T
+	version   int
+	state     computedState
+	deps      tracker
+	observers observers
+}
+
+// NewComputed creates a value computed by fn
+// A result equal (==) to the previous one does not notify the observers
+//
+// Usage examples :
+//
+//	fullName := reactive.NewComputed(func() string {
+//	    return firstName.Get() + " " + lastName.Get()
+//	})
+func NewComputed[T comparable](fn func() T) *Computed[T] {
+	return NewComputedFunc(fn, func(a, b T) bool { return a == b })
+}
+
+// NewComputedFunc is NewComputed for the values compared with equal
+// With a nil equal every computation is a change
+func NewComputedFunc[T any](fn func() T, equal func(a, b T) bool) *Computed[T] {
+	return &Computed[T]{fn: fn, equal: equal}
+}
+
+// Get returns the value, computing it if needed, and subscribes the running Computed or Effect to it
+func (c *Computed[T]) Get() T {
+	version := c.refresh()
+	if current != nil {
+		current.record(c, version)
+	}
+	return c.value
+}
+
+// Peek returns the value, computing it if needed, without subscribing to it
+func (c *Computed[T]) Peek() T {
+	c.refresh()
+	return c.value
+}
+
+// refresh computes the value again if one of its sources changed
+func (c *Computed[T]) refresh() int {
+	switch c.state {
+	case computedClean:
+		return c.version
+	case computedStale:
+		if !c.deps.changed() {
+			c.state = computedClean
+			return c.version
+		}
+	}
+
+	var value T
+	c.deps.track(c, func() { value = c.fn() })
+	if c.state == computedInitial || c.equal == nil || !c.equal(c.value, value) {
+		c.value = value
+		c.version++
+	}
+	c.state = computedClean
+	return c.version
+}
+
+// markStale marks the value to be checked, and its observers with it
+func (c *Computed[T]) markStale() {
+	if c.state != computedClean {
+		return
+	}
+	c.state = computedStale
+	for _, o := range c.observers {
+		o.markStale()
+	}
+}
+
+func (c *Computed[T]) subscribe(o observer) {
+	c.observers.subscribe(o)
+}
+
+func (c *Computed[T]) unsubscribe(o observer) {
+	c.observers.unsubscribe(o)
+}
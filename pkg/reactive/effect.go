@@ -0,0 +1,47 @@
+package reactive
+
+// Effect runs a function again whenever the signals and computed values it read change
+type Effect struct {
+	fn       func()
+	deps     tracker
+	queued   bool
+	disposed bool
+}
+
+// NewEffect runs fn at once, then again after every change of what it read
+// The effect must be disposed once it is no longer needed: until then its sources keep it alive.
+//
+// Usage examples :
+//
+//	effect := reactive.NewEffect(func() {
+//	    document.Set("title", fmt.Sprintf("(%d) Inbox", unread.Get()))
+//	})
+//	defer effect.Dispose()
+func NewEffect(fn func()) *Effect {
+	e := &Effect{fn: fn}
+	e.run()
+	return e
+}
+
+// Dispose stops the effect and unsubscribes it from its sources
+func (e *Effect) Dispose() {
+	e.disposed = true
+	e.deps.untrack(e)
+}
+
+func (e *Effect) run() {
+	e.deps.track(e, e.fn)
+	// Disposed while running: the sources it just subscribed to must not keep it
+	if e.disposed {
+		e.deps.untrack(e)
+	}
+}
+
+// markStale queues the effect, it runs once the change is propagated
+func (e *Effect) markStale() {
+	if e.queued || e.disposed {
+		return
+	}
+	e.queued = true
+	pending = append(pending, e)
+}
@@ -0,0 +1,186 @@
+// Package reactive provides fine-grained reactive values: signals, computed values and effects.
+//
+// A Signal holds a value. A Computed derives a value from signals and other computed values,
+// and an Effect runs a function again whenever what it read changes. The dependencies are
+// tracked automatically: reading a signal or a computed value inside a Computed or an Effect
+// subscribes it to that value.
+//
+// Basic Usage:
+//
+//	count := reactive.NewSignal(0)
+//	double := reactive.NewComputed(func() int { return count.Get() * 2 })
+//	reactive.NewEffect(func() {
+//	    fmt.Println("double is", double.Get())
+//	})
+//	count.Set(2) // Prints "double is 4"
+//
+// The effects run synchronously once the change is propagated, each one at most once per
+// change even when several of its dependencies changed, and only when a value it read actually
+// changed: a computed value recomputed to the same result does not re-run its observers.
+// Batch groups several changes into a single propagation.
+//
+// In the browser, vdom.Bind ties the text, a prop or the style of a node to reactive values:
+// the renderer then patches that DOM node alone when they change, without re-rendering the
+// component nor diffing its tree.
+//
+// The graph is not safe for concurrent use. In the browser everything runs on the event loop;
+// elsewhere the signals must be set from one goroutine at a time.
+package reactive
+
+// source is a value of the graph which can be read by the observers: a Signal or a Computed
+type source interface {
+	// refresh brings the value up to date and returns its version, incremented on every change
+	refresh() int
+	subscribe(o observer)
+	unsubscribe(o observer)
+}
+
+// observer is notified when one of its sources may have changed: a Computed or an Effect
+type observer interface {
+	markStale()
+}
+
+// dependency is a source read by an observer, with the version it had when it was read
+type dependency struct {
+	source  source
+	version int
+}
+
+// tracker records the sources read by an observer while it runs
+type tracker struct {
+	deps []dependency
+	seen map[source]bool
+}
+
+func (t *tracker) record(s source, version int) {
+	if t.seen[s] {
+		return
+	}
+	if t.seen == nil {
+		t.seen = make(map[source]bool)
+	}
+	t.seen[s] = true
+	t.deps = append(t.deps, dependency{source: s, version: version})
+}
+
+// changed reports whether one of the sources changed since it was read
+// The computed sources are brought up to date on the way
+func (t *tracker) changed() bool {
+	for _, dep := range t.deps {
+		if dep.source.refresh() != dep.version {
+			return true
+		}
+	}
+	return false
+}
+
+// track runs fn while recording the sources it reads, and subscribes o to them instead of the
+// sources of its previous run
+func (t *tracker) track(o observer, fn func()) {
+	previousDeps := t.deps
+	t.deps, t.seen = nil, nil
+
+	previous := current
+	current = t
+	defer func() {
+		current = previous
+		for _, dep := range previousDeps {
+			dep.source.unsubscribe(o)
+		}
+		for _, dep := range t.deps {
+			dep.source.subscribe(o)
+		}
+	}()
+	fn()
+}
+
+// untrack unsubscribes o from all its sources
+func (t *tracker) untrack(o observer) {
+	for _, dep := range t.deps {
+		dep.source.unsubscribe(o)
+	}
+	t.deps, t.seen = nil, nil
+}
+
+// observers is the list of the observers of a source, in the order they subscribed
+type observers []observer
+
+func (l *observers) subscribe(o observer) {
+	for _, existing := range *l {
+		if existing == o {
+			return
+		}
+	}
+	*l = append(*l, o)
+}
+
+func (l *observers) unsubscribe(o observer) {
+	for i, existing := range *l {
+		if existing == o {
+			*l = append((*l)[:i], (*l)[i+1:]...)
+			return
+		}
+	}
+}
+
+// notify marks the observers stale and runs the effects, unless a batch is running
+func (l observers) notify() {
+	batchDepth++
+	for _, o := range append(observers(nil), l...) {
+		o.markStale()
+	}
+	batchDepth--
+	flush()
+}
+
+var (
+	current    *tracker  // Tracker of the observer running, nil outside of any
+	batchDepth int       // Number of nested batches running
+	pending    []*Effect // Effects waiting to run at the end of the batch
+	flushing   bool      // The pending effects are running
+)
+
+// flush runs the pending effects
+// The effects queued while flushing, by the changes the effects make, run in the same flush
+func flush() {
+	if batchDepth > 0 || flushing {
+		return
+	}
+	flushing = true
+	defer func() { flushing = false }()
+
+	for len(pending) > 0 {
+		effect := pending[0]
+		pending = pending[1:]
+		effect.queued = false
+		if !effect.disposed && effect.deps.changed() {
+			effect.run()
+		}
+	}
+}
+
+// Batch runs fn and propagates the changes it makes once it returns
+// The effects depending on several of the signals set by fn run once
+//
+// Usage examples :
+//
+//	reactive.Batch(func() {
+//	    firstName.Set("Ada")
+//	    lastName.Set("Lovelace")
+//	})
+func Batch(fn func()) {
+	batchDepth++
+	defer func() {
+		batchDepth--
+		flush()
+	}()
+	fn()
+}
+
+// Untrack runs fn without subscribing the running observer to what fn reads
+func Untrack(fn func()) {
+	previous := current
+	current = nil
+	defer func() { current = previous }()
+	fn()
+}
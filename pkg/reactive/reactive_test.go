@@ -0,0 +1,243 @@
+package reactive
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiamondRunsEffectOnce(t *testing.T) {
+	a := NewSignal(1)
+	computations := 0
+	double := NewComputed(func() int { return a.Get() * 2 })
+	next := NewComputed(func() int { return a.Get() + 1 })
+	sum := NewComputed(func() int {
+		computations++
+		return double.Get() + next.Get()
+	})
+
+	var seen []int
+	effect := NewEffect(func() { seen = append(seen, sum.Get()) })
+	defer effect.Dispose()
+
+	a.Set(2)
+	a.Set(3)
+
+	// Each change runs the effect once, never with half of the diamond updated
+	if want := []int{4, 7, 10}; !reflect.DeepEqual(seen, want) {
+		t.Errorf("effect saw %v, want %v", seen, want)
+	}
+	if computations != 3 {
+		t.Errorf("sum computed %d times, want 3", computations)
+	}
+}
+
+func TestComputedEqualSuppressesObservers(t *testing.T) {
+	n := NewSignal(1)
+	computations := 0
+	odd := NewComputed(func() bool {
+		computations++
+		return n.Get()%2 == 1
+	})
+
+	runs := 0
+	effect := NewEffect(func() {
+		odd.Get()
+		runs++
+	})
+	defer effect.Dispose()
+
+	n.Set(3)
+	if computations != 2 || runs != 1 {
+		t.Errorf("computed %d times, effect ran %d times, want 2 and 1", computations, runs)
+	}
+
+	n.Set(4)
+	if runs != 2 || odd.Peek() {
+		t.Errorf("effect ran %d times with odd %v, want 2 and false", runs, odd.Peek())
+	}
+}
+
+func TestComputedFuncWithoutEqual(t *testing.T) {
+	n := NewSignal(1)
+	list := NewComputedFunc(func() []int { return []int{n.Get() % 2} }, nil)
+
+	runs := 0
+	effect := NewEffect(func() {
+		list.Get()
+		runs++
+	})
+	defer effect.Dispose()
+
+	n.Set(3) // Same content, but every computation is a change
+	if runs != 2 {
+		t.Errorf("effect ran %d times, want 2", runs)
+	}
+}
+
+func TestComputedIsLazy(t *testing.T) {
+	n := NewSignal(1)
+	computations := 0
+	square := NewComputed(func() int {
+		computations++
+		return n.Get() * n.Get()
+	})
+
+	if computations != 0 {
+		t.Fatalf("computed %d times before being read", computations)
+	}
+	square.Get()
+	square.Get()
+	n.Set(2)
+	n.Set(3)
+	if got := square.Get(); got != 9 || computations != 2 {
+		t.Errorf("square = %d after %d computations, want 9 after 2", got, computations)
+	}
+}
+
+func TestSignalEqualSetIsNoChange(t *testing.T) {
+	name := NewSignal("ada")
+	runs := 0
+	effect := NewEffect(func() {
+		name.Get()
+		runs++
+	})
+	defer effect.Dispose()
+
+	name.Set("ada")
+	name.Update(func(v string) string { return v + "!" })
+	if runs != 2 {
+		t.Errorf("effect ran %d times, want 2", runs)
+	}
+}
+
+func TestBatch(t *testing.T) {
+	first := NewSignal("Ada")
+	last := NewSignal("Byron")
+
+	var seen []string
+	effect := NewEffect(func() { seen = append(seen, first.Get()+" "+last.Get()) })
+	defer effect.Dispose()
+
+	Batch(func() {
+		first.Set("Augusta")
+		Batch(func() {
+			last.Set("King")
+		})
+		if len(seen) != 1 {
+			t.Errorf("effect ran inside the batch: %v", seen)
+		}
+		last.Set("Lovelace")
+	})
+
+	if want := []string{"Ada Byron", "Augusta Lovelace"}; !reflect.DeepEqual(seen, want) {
+		t.Errorf("effect saw %v, want %v", seen, want)
+	}
+
+	// Setting the current values changes nothing
+	Batch(func() {
+		first.Set("Augusta")
+		last.Set("Lovelace")
+	})
+	if len(seen) != 2 {
+		t.Errorf("effect ran without a change: %v", seen)
+	}
+}
+
+func TestEffectDisposedDuringItsRun(t *testing.T) {
+	n := NewSignal(0)
+	runs := 0
+	var effect *Effect
+	effect = NewEffect(func() {
+		n.Get()
+		runs++
+		if runs == 2 {
+			effect.Dispose()
+		}
+	})
+
+	n.Set(1)
+	n.Set(2)
+	if runs != 2 {
+		t.Errorf("effect ran %d times, want 2", runs)
+	}
+	if len(n.observers) != 0 {
+		t.Errorf("the disposed effect is still subscribed: %d observers", len(n.observers))
+	}
+}
+
+func TestEffectDisposedWhilePending(t *testing.T) {
+	n := NewSignal(0)
+	var second *Effect
+	secondRuns := 0
+
+	first := NewEffect(func() {
+		if n.Get() == 1 {
+			second.Dispose()
+		}
+	})
+	defer first.Dispose()
+	second = NewEffect(func() {
+		n.Get()
+		secondRuns++
+	})
+
+	n.Set(1) // Both are queued, the first one disposes the second
+	if secondRuns != 1 {
+		t.Errorf("the disposed effect ran %d times, want 1", secondRuns)
+	}
+}
+
+func TestUntrack(t *testing.T) {
+	tracked := NewSignal(1)
+	untracked := NewSignal(10)
+
+	var seen []int
+	effect := NewEffect(func() {
+		value := tracked.Get()
+		Untrack(func() { value += untracked.Get() })
+		seen = append(seen, value)
+	})
+	defer effect.Dispose()
+
+	untracked.Set(20)
+	if len(seen) != 1 {
+		t.Errorf("effect ran for an untracked change: %v", seen)
+	}
+
+	tracked.Set(2) // Reads the latest untracked value
+	if want := []int{11, 22}; !reflect.DeepEqual(seen, want) {
+		t.Errorf("effect saw %v, want %v", seen, want)
+	}
+
+	// Peek does not subscribe either
+	peeked := NewEffect(func() { untracked.Peek() })
+	defer peeked.Dispose()
+	if len(untracked.observers) != 0 {
+		t.Errorf("untracked has %d observers, want 0", len(untracked.observers))
+	}
+}
+
+func TestDynamicDependencies(t *testing.T) {
+	useA := NewSignal(true)
+	a := NewSignal("a")
+	b := NewSignal("b")
+
+	runs := 0
+	effect := NewEffect(func() {
+		runs++
+		if useA.Get() {
+			a.Get()
+		} else {
+			b.Get()
+		}
+	})
+	defer effect.Dispose()
+
+	b.Set("b2") // Not read yet
+	useA.Set(false)
+	a.Set("a2") // Not read anymore
+	b.Set("b3")
+	if runs != 3 {
+		t.Errorf("effect ran %d times, want 3", runs)
+	}
+}
@@ -0,0 +1,65 @@
+package reactive
+
+// Signal holds a value and notifies the observers which read it when it changes
+type Signal[T any] struct {
+	value     T
+	equal     func(a, b T) bool
+	version   int
+	observers observers
+}
+
+// NewSignal creates a signal holding the value
+// Setting a value equal (==) to the current one changes nothing
+func NewSignal[T comparable](value T) *Signal[T] {
+	return NewSignalFunc(value, func(a, b T) bool { return a == b })
+}
+
+// NewSignalFunc is NewSignal for the values compared with equal, such as slices with slices.Equal
+// With a nil equal every Set is a change
+func NewSignalFunc[T any](value T, equal func(a, b T) bool) *Signal[T] {
+	return &Signal[T]{value: value, equal: equal}
+}
+
+// Get returns the value and subscribes the running Computed or Effect to the signal
+func (s *Signal[T]) Get() T {
+	if current != nil {
+		current.record(s, s.version)
+	}
+	return s.value
+}
+
+// Peek returns the value without subscribing to the signal
+func (s *Signal[T]) Peek() T {
+	return s.value
+}
+
+// Set changes the value and runs the effects depending on it
+func (s *Signal[T]) Set(value T) {
+	if s.equal != nil && s.equal(s.value, value) {
+		return
+	}
+	s.value = value
+	s.version++
+	s.observers.notify()
+}
+
+// Update sets the value returned by fn from the current one
+//
+// Usage examples :
+//
+//	count.Update(func(n int) int { return n + 1 })
+func (s *Signal[T]) Update(fn func(value T) T) {
+	s.Set(fn(s.value))
+}
+
+func (s *Signal[T]) refresh() int {
+	return s.version
+}
+
+func (s *Signal[T]) subscribe(o observer) {
+	s.observers.subscribe(o)
+}
+
+func (s *Signal[T]) unsubscribe(o observer) {
+	s.observers.unsubscribe(o)
+}
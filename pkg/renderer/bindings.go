@@ -0,0 +1,136 @@
+//go:build js && wasm
+
+package renderer
+
+import (
+	"syscall/js"
+
+	"github.com/AureClai/vortex/pkg/reactive"
+	"github.com/AureClai/vortex/pkg/vdom"
+)
+
+// bindingIDProperty is the property set on every DOM node with bound parts
+// js.Value cannot be used as a map key, the node is identified by this id instead
+const bindingIDProperty = "__vortexBindingID"
+
+// boundNode holds the effects patching the bound parts of a DOM node (see vdom.Binding)
+// The effects write to the current vnode of the DOM node, handed over by the diff (diff.OpBind),
+// so the tree stays in sync with the DOM for the next diff
+type boundNode struct {
+	node  *vdom.VNode
+	parts map[string]*boundPart
+}
+
+// boundPart is the effect following the binding of a part: the text, the style or a prop
+type boundPart struct {
+	binding *vdom.Binding
+	effect  *reactive.Effect
+}
+
+// bind starts following the bindings of the vnode, its DOM node being created or patched
+// The parts bound to the same binding as before keep their effect, the others are switched
+func (r *Renderer) bind(vnode *vdom.VNode) {
+	bindings := vnode.Bindings()
+	bound := r.boundOf(vnode.Element, len(bindings) > 0)
+	if bound == nil {
+		return
+	}
+	bound.node = vnode
+
+	followed := make(map[string]*vdom.Binding, len(bound.parts))
+	for name, part := range bound.parts {
+		followed[name] = part.binding
+	}
+	stopped, started := vdom.BindingChanges(followed, bindings)
+	for _, name := range stopped {
+		bound.parts[name].effect.Dispose()
+		delete(bound.parts, name)
+	}
+	for _, name := range started {
+		bound.parts[name] = r.follow(bound, name, bindings[name])
+	}
+
+	if len(bound.parts) == 0 {
+		r.forgetBound(vnode.Element)
+	}
+}
+
+// follow creates the effect writing the value of the binding to the part of the bound node
+// It runs at once, the node is then patched after every change of the value
+func (r *Renderer) follow(bound *boundNode, name string, binding *vdom.Binding) *boundPart {
+	effect := reactive.NewEffect(func() {
+		value := binding.Read()
+		// What the DOM writes read must not become a dependency of the binding
+		reactive.Untrack(func() {
+			r.applyBinding(bound.node, name, value)
+		})
+	})
+	return &boundPart{binding: binding, effect: effect}
+}
+
+// applyBinding writes the value of a bound part to the node and to its DOM node, without diff
+func (r *Renderer) applyBinding(vnode *vdom.VNode, name string, value interface{}) {
+	vnode.ApplyBinding(name, value)
+	switch name {
+	case vdom.TextPart:
+		if vnode.Element.Get("textContent").String() != vnode.Text {
+			vnode.Element.Set("textContent", vnode.Text)
+		}
+
+	case vdom.StylePart:
+		r.updateStyle(vnode, vnode)
+		r.styles.flush()
+
+	default:
+		r.setProp(vnode, name, value)
+	}
+}
+
+// releaseBindings stops the effects of every node of a subtree leaving the DOM
+func (r *Renderer) releaseBindings(vnode *vdom.VNode) {
+	if vnode == nil {
+		return
+	}
+	if vnode.Type == vdom.VNodeComponent {
+		r.releaseBindings(vnode.Rendered)
+		return
+	}
+
+	if bound := r.boundOf(vnode.Element, false); bound != nil {
+		for _, part := range bound.parts {
+			part.effect.Dispose()
+		}
+		r.forgetBound(vnode.Element)
+	}
+
+	for _, child := range vnode.Children {
+		r.releaseBindings(child)
+	}
+}
+
+// boundOf returns the bound node of the DOM node
+// When create is true a new one is registered for a DOM node that has none
+func (r *Renderer) boundOf(domNode js.Value, create bool) *boundNode {
+	if !domNode.Truthy() {
+		return nil
+	}
+
+	if id := domNode.Get(bindingIDProperty); !id.IsUndefined() {
+		return r.bindings[id.Int()]
+	}
+	if !create {
+		return nil
+	}
+
+	r.nextBindingID++
+	bound := &boundNode{parts: make(map[string]*boundPart)}
+	r.bindings[r.nextBindingID] = bound
+	domNode.Set(bindingIDProperty, r.nextBindingID)
+	return bound
+}
+
+// forgetBound unregisters the bound node of the DOM node
+func (r *Renderer) forgetBound(domNode js.Value) {
+	delete(r.bindings, domNode.Get(bindingIDProperty).Int())
+	domNode.Delete(bindingIDProperty)
+}
@@ -0,0 +1,104 @@
+//go:build js && wasm
+
+package renderer
+
+import (
+	"syscall/js"
+
+	"github.com/AureClai/vortex/pkg/vdom"
+)
+
+// listenerIDProperty is the property set on every DOM element owning listeners
+// js.Value cannot be used as a map key, the element is identified by this id instead
+const listenerIDProperty = "__vortexListenerID"
+
+// listener is a DOM event listener bound to an element
+// The js.Func is registered once and forwards to the current handler,
+// so swapping the handler between renders does not touch the DOM
+type listener struct {
+	handler func(event vdom.Event)
+	fn      js.Func
+}
+
+// listenerSet holds the listeners of one element by event name
+type listenerSet map[string]*listener
+
+// setListener binds the handler to the event of the element
+// The DOM listener is only added the first time, later calls swap the handler
+func (r *Renderer) setListener(element js.Value, event string, handler func(event vdom.Event)) {
+	set := r.listenersOf(element, true)
+	if l, exists := set[event]; exists {
+		l.handler = handler
+		return
+	}
+
+	l := &listener{handler: handler}
+	l.fn = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		l.handler(args[0])
+		return nil
+	})
+	set[event] = l
+	element.Call("addEventListener", event, l.fn)
+}
+
+// removeListener removes the listener of the event from the element and releases it
+func (r *Renderer) removeListener(element js.Value, event string) {
+	set := r.listenersOf(element, false)
+	l, exists := set[event]
+	if !exists {
+		return
+	}
+
+	element.Call("removeEventListener", event, l.fn)
+	l.fn.Release()
+	delete(set, event)
+}
+
+// releaseListeners removes and releases the listeners of every element of the subtree
+// It must be called when the subtree leaves the DOM, otherwise the js.Func leak
+func (r *Renderer) releaseListeners(vnode *vdom.VNode) {
+	if vnode == nil {
+		return
+	}
+	if vnode.Type == vdom.VNodeComponent {
+		r.releaseListeners(vnode.Rendered)
+		return
+	}
+	if vnode.Type == vdom.VNodeText {
+		return
+	}
+
+	if set := r.listenersOf(vnode.Element, false); vnode.Type == vdom.VNodeElement && set != nil {
+		for event, l := range set {
+			vnode.Element.Call("removeEventListener", event, l.fn)
+			l.fn.Release()
+		}
+		delete(r.listeners, vnode.Element.Get(listenerIDProperty).Int())
+		vnode.Element.Delete(listenerIDProperty)
+	}
+
+	for _, child := range vnode.Children {
+		r.releaseListeners(child)
+	}
+}
+
+// listenersOf returns the listener set of the element
+// When create is true a new set is registered for an element that has none
+func (r *Renderer) listenersOf(element js.Value, create bool) listenerSet {
+	if !element.Truthy() {
+		return nil
+	}
+
+	if id := element.Get(listenerIDProperty); !id.IsUndefined() {
+		return r.listeners[id.Int()]
+	}
+	if !create {
+		return nil
+	}
+
+	r.nextListenerID++
+	set := make(listenerSet)
+	r.listeners[r.nextListenerID] = set
+	element.Set(listenerIDProperty, r.nextListenerID)
+	return set
+}
@@ -0,0 +1,271 @@
+//go:build js && wasm
+
+package renderer
+
+import (
+	"fmt"
+	"strings"
+	"syscall/js"
+
+	"github.com/AureClai/vortex/pkg/vdom"
+)
+
+// DOM node types met while hydrating
+const (
+	elementNode = 1
+	textNode    = 3
+	commentNode = 8
+)
+
+// SetDevMode enables the development checks, such as the hydration mismatch reports
+func (r *Renderer) SetDevMode(enabled bool) {
+	r.devMode = enabled
+}
+
+// Hydrate takes over the markup rendered outside the browser (see the ssr package)
+// instead of rendering the tree from scratch. The existing DOM under the container is walked
+// along the tree: the nodes are bound to their Element, the listeners are attached and the style
+// classes already in the stylesheet are registered, without recreating anything.
+//
+// Where the DOM does not match the tree it is repaired, and the mismatch is reported in dev mode.
+// The OnMount hooks run once the whole tree is hydrated.
+//
+// Usage examples :
+//
+//	r := renderer.NewRenderer("app")
+//	r.SetDevMode(true)
+//	r.Hydrate(vdom.NewComponentVNode(app, nil))
+func (r *Renderer) Hydrate(vnode *vdom.VNode) {
+	r.styles.register()
+
+	next := r.hydrateChildren(r.container, r.container.Get("firstChild"), []*vdom.VNode{vnode})
+	r.removeExtraNodes(r.container, next)
+
+	r.currentVNode = vnode
+	r.styles.flush()
+	r.queueMount(vnode)
+	r.runHooks()
+}
+
+// hydrateChildren binds the children to the DOM nodes starting at domNode
+// It returns the DOM node following the last child
+func (r *Renderer) hydrateChildren(parent, domNode js.Value, children []*vdom.VNode) js.Value {
+	for _, child := range children {
+		domNode = r.hydrateNode(parent, domNode, child)
+	}
+	return domNode
+}
+
+// hydrateNode binds the vnode to the DOM node and returns the DOM node following it
+func (r *Renderer) hydrateNode(parent, domNode js.Value, vnode *vdom.VNode) js.Value {
+	if vnode == nil {
+		return domNode
+	}
+
+	if vnode.Type == vdom.VNodeComponent {
+		return r.hydrateNode(parent, domNode, vnode.RenderComponent())
+	}
+
+	domNode = skipIgnored(domNode, vnode)
+
+	switch vnode.Type {
+	case vdom.VNodeText:
+		// An empty text has no node in the parsed markup
+		if vnode.Text == "" || !isNodeType(domNode, textNode) {
+			if vnode.Text != "" {
+				r.reportMismatch("expected text %q, found %s", vnode.Text, describeNode(domNode))
+			}
+			parent.Call("insertBefore", r.createDomNode(vnode), domNode)
+			// An element in place of the text is replaced, as in the element mismatch below
+			// An empty text has no node: what follows belongs to the next vnode, as the comment markers
+			if vnode.Text != "" && isNodeType(domNode, elementNode) {
+				next := domNode.Get("nextSibling")
+				parent.Call("removeChild", domNode)
+				return next
+			}
+			return domNode
+		}
+		if domNode.Get("data").String() != vnode.Text {
+			r.reportMismatch("expected text %q, found %q", vnode.Text, domNode.Get("data").String())
+			domNode.Set("data", vnode.Text)
+		}
+		vnode.Element = domNode
+		r.bind(vnode)
+		return domNode.Get("nextSibling")
+
+	case vdom.VNodeFragment:
+		if !isComment(domNode, vdom.FragmentStartMarker) {
+			r.reportMismatch("expected a fragment, found %s", describeNode(domNode))
+			parent.Call("insertBefore", r.createDomNode(vnode), domNode)
+			return domNode
+		}
+		vnode.Element = domNode
+		next := r.hydrateChildren(parent, domNode.Get("nextSibling"), vnode.Children)
+
+		// Remove what is left before the closing marker
+		for next.Truthy() && !isComment(next, vdom.FragmentEndMarker) {
+			r.reportMismatch("unexpected %s in a fragment", describeNode(next))
+			following := next.Get("nextSibling")
+			parent.Call("removeChild", next)
+			next = following
+		}
+		if !next.Truthy() {
+			r.reportMismatch("missing the end of a fragment")
+			vnode.EndElement = js.Global().Get("document").Call("createComment", vdom.FragmentEndMarker)
+			parent.Call("appendChild", vnode.EndElement)
+			return js.Null()
+		}
+		vnode.EndElement = next
+		return next.Get("nextSibling")
+
+	case vdom.VNodePortal:
+		// The markup only holds the placeholder, the children are created in the target
+		if !isComment(domNode, vdom.PortalMarker) {
+			r.reportMismatch("expected a portal, found %s", describeNode(domNode))
+			parent.Call("insertBefore", r.createDomNode(vnode), domNode)
+			return domNode
+		}
+		vnode.Element = domNode
+		target := r.portalTarget(vnode)
+		for _, child := range vnode.Children {
+			if childNode := r.createDomNode(child); childNode.Truthy() {
+				target.Call("appendChild", childNode)
+			}
+		}
+		return domNode.Get("nextSibling")
+
+	case vdom.VNodeElement:
+		if !isNodeType(domNode, elementNode) || !strings.EqualFold(domNode.Get("tagName").String(), vnode.Tag) {
+			r.reportMismatch("expected <%s>, found %s", vnode.Tag, describeNode(domNode))
+			parent.Call("insertBefore", r.createDomNode(vnode), domNode)
+			if domNode.Truthy() {
+				next := domNode.Get("nextSibling")
+				parent.Call("removeChild", domNode)
+				return next
+			}
+			return domNode
+		}
+
+		vnode.Element = domNode
+		r.hydrateProps(vnode)
+		for event, handler := range vnode.EventHandlers {
+			r.setListener(domNode, event, handler)
+		}
+		r.processStyle(vnode)
+		r.bind(vnode)
+
+		// The server wrote the value of a textarea as its content (see the ssr package)
+		if _, hasValue := vnode.Props["value"]; hasValue && vnode.Tag == "textarea" {
+			return domNode.Get("nextSibling")
+		}
+
+		next := r.hydrateChildren(domNode, domNode.Get("firstChild"), vnode.Children)
+		r.removeExtraNodes(domNode, next)
+		return domNode.Get("nextSibling")
+	}
+
+	return domNode
+}
+
+// hydrateProps checks the attributes of the element against the props of the vnode
+// The DOM properties are left untouched: the user may have typed before the hydration
+func (r *Renderer) hydrateProps(vnode *vdom.VNode) {
+	element := vnode.Element
+	for name, value := range vnode.Props {
+		value = vdom.ResolveProp(value)
+		var matches bool
+		switch vdom.PropKindOf(name, value) {
+		case vdom.PropProperty:
+			continue
+		case vdom.PropClass:
+			matches = element.Get("className").String() == classWithStyle(vdom.FormatProp(value), vnode)
+		case vdom.PropBoolean:
+			matches = element.Call("hasAttribute", name).Bool() == vdom.PropBool(value)
+		default:
+			attribute := element.Call("getAttribute", name)
+			if value == nil {
+				matches = attribute.IsNull()
+			} else {
+				matches = !attribute.IsNull() && attribute.String() == vdom.FormatProp(value)
+			}
+		}
+		if !matches {
+			r.reportMismatch("prop %q of <%s> differs", name, vnode.Tag)
+			r.setProp(vnode, name, value)
+		}
+	}
+}
+
+// removeExtraNodes removes the DOM nodes left after the last child of a parent
+func (r *Renderer) removeExtraNodes(parent, domNode js.Value) {
+	for domNode.Truthy() {
+		next := domNode.Get("nextSibling")
+		if isStyleElement(domNode) {
+			domNode = next
+			continue
+		}
+		if !isIgnored(domNode) {
+			r.reportMismatch("unexpected %s", describeNode(domNode))
+		}
+		parent.Call("removeChild", domNode)
+		domNode = next
+	}
+}
+
+// reportMismatch logs a difference between the markup and the tree, in dev mode only
+func (r *Renderer) reportMismatch(format string, args ...interface{}) {
+	if r.devMode {
+		fmt.Printf("WARNING: hydration mismatch: "+format+"\n", args...)
+	}
+}
+
+// skipIgnored skips the DOM nodes without counterpart in the tree:
+// the separators between texts and, before a node that is not a text, the blank texts
+// and the stylesheet
+func skipIgnored(domNode js.Value, vnode *vdom.VNode) js.Value {
+	for domNode.Truthy() {
+		if isComment(domNode, "") || (vnode.Type != vdom.VNodeText && isIgnored(domNode)) {
+			domNode = domNode.Get("nextSibling")
+			continue
+		}
+		break
+	}
+	return domNode
+}
+
+// isIgnored reports whether the DOM node is a separator, a blank text or the stylesheet
+// written with the markup
+func isIgnored(domNode js.Value) bool {
+	if isComment(domNode, "") || isStyleElement(domNode) {
+		return true
+	}
+	return isNodeType(domNode, textNode) && strings.TrimSpace(domNode.Get("data").String()) == ""
+}
+
+// isStyleElement reports whether the DOM node is the <style> element holding the styles
+func isStyleElement(domNode js.Value) bool {
+	return isNodeType(domNode, elementNode) && domNode.Get("id").String() == styleElementID
+}
+
+func isNodeType(domNode js.Value, nodeType int) bool {
+	return domNode.Truthy() && domNode.Get("nodeType").Int() == nodeType
+}
+
+func isComment(domNode js.Value, data string) bool {
+	return isNodeType(domNode, commentNode) && domNode.Get("data").String() == data
+}
+
+// describeNode returns a short description of a DOM node for the mismatch reports
+func describeNode(domNode js.Value) string {
+	switch {
+	case !domNode.Truthy():
+		return "nothing"
+	case isNodeType(domNode, elementNode):
+		return "<" + strings.ToLower(domNode.Get("tagName").String()) + ">"
+	case isNodeType(domNode, textNode):
+		return fmt.Sprintf("text %q", domNode.Get("data").String())
+	case isNodeType(domNode, commentNode):
+		return fmt.Sprintf("comment %q", domNode.Get("data").String())
+	}
+	return "node"
+}
@@ -0,0 +1,74 @@
+//go:build js && wasm
+
+package renderer
+
+import (
+	"github.com/AureClai/vortex/pkg/vdom"
+)
+
+// queueMount queues the OnMount hook of every component of a created subtree, children first
+// The hooks run once all the operations of the patch are applied (see applyOps)
+func (r *Renderer) queueMount(vnode *vdom.VNode) {
+	if vnode == nil {
+		return
+	}
+
+	if vnode.Type == vdom.VNodeComponent {
+		r.queueMount(vnode.Rendered)
+		if mounter, ok := vnode.Component.(vdom.Mounter); ok {
+			r.hooks = append(r.hooks, mounter.OnMount)
+		}
+		return
+	}
+
+	for _, child := range vnode.Children {
+		r.queueMount(child)
+	}
+}
+
+// queueUpdate queues the OnUpdate hook of a patched component
+// When the parent rendered another instance, the previous one is unmounted and the new one
+// mounted instead: the instance which received OnMount is the one receiving OnUnmount
+func (r *Renderer) queueUpdate(oldVNode, vnode *vdom.VNode) {
+	if oldVNode != nil && !vdom.SameInstance(oldVNode.Component, vnode.Component) {
+		if unmounter, ok := oldVNode.Component.(vdom.Unmounter); ok {
+			unmounter.OnUnmount()
+		}
+		if mounter, ok := vnode.Component.(vdom.Mounter); ok {
+			r.hooks = append(r.hooks, mounter.OnMount)
+		}
+		return
+	}
+
+	if updater, ok := vnode.Component.(vdom.Updater); ok {
+		r.hooks = append(r.hooks, updater.OnUpdate)
+	}
+}
+
+// unmount calls the OnUnmount hook of every component of a subtree about to be removed, parents first
+func (r *Renderer) unmount(vnode *vdom.VNode) {
+	if vnode == nil {
+		return
+	}
+
+	if vnode.Type == vdom.VNodeComponent {
+		if unmounter, ok := vnode.Component.(vdom.Unmounter); ok {
+			unmounter.OnUnmount()
+		}
+		r.unmount(vnode.Rendered)
+		return
+	}
+
+	for _, child := range vnode.Children {
+		r.unmount(child)
+	}
+}
+
+// runHooks runs the queued OnMount and OnUpdate hooks
+func (r *Renderer) runHooks() {
+	hooks := r.hooks
+	r.hooks = nil
+	for _, hook := range hooks {
+		hook()
+	}
+}
@@ -0,0 +1,179 @@
+//go:build js && wasm
+
+package renderer
+
+import (
+	"fmt"
+	"syscall/js"
+
+	"github.com/AureClai/vortex/pkg/diff"
+	"github.com/AureClai/vortex/pkg/vdom"
+)
+
+// applyOps applies the operations computed by the diff package to the DOM
+// root is the DOM parent of the diffed tree, used by the operations without parent node
+// The styles of the patch are injected at once when it is applied (see styles.go),
+// then the OnMount and OnUpdate hooks run (see lifecycle.go)
+func (r *Renderer) applyOps(root js.Value, ops []diff.Op) {
+	for _, op := range ops {
+		r.applyOp(root, op)
+	}
+	r.styles.flush()
+	r.runHooks()
+}
+
+// applyOp applies a single operation to the DOM
+func (r *Renderer) applyOp(root js.Value, op diff.Op) {
+	parent := r.parentElement(root, op.Parent)
+
+	switch op.Kind {
+	case diff.OpCreate:
+		domNode := r.createDomNode(op.Node)
+		parent.Call("insertBefore", domNode, anchorElement(op.Parent, op.Anchor))
+		r.queueMount(op.Node)
+
+	case diff.OpRemove:
+		r.unmount(op.Node)
+		removeDomNodes(op.Node)
+		r.removePortals(op.Node)
+		r.releaseListeners(op.Node)
+		r.releaseBindings(op.Node)
+		r.releaseStyles(op.Node)
+
+	case diff.OpReplace:
+		r.unmount(op.Old)
+		domNode := r.createDomNode(op.Node)
+		// Inserted next to the old node, wherever it is: in the target of a portal it may not be parent
+		oldElement := op.Old.Host().Element
+		oldElement.Get("parentNode").Call("insertBefore", domNode, oldElement)
+		removeDomNodes(op.Old)
+		r.removePortals(op.Old)
+		r.releaseListeners(op.Old)
+		r.releaseBindings(op.Old)
+		r.releaseStyles(op.Old)
+		r.queueMount(op.Node)
+
+	case diff.OpMove:
+		anchor := anchorElement(op.Parent, op.Anchor)
+		for _, domNode := range domNodes(op.Node) {
+			parent.Call("insertBefore", domNode, anchor)
+		}
+
+	case diff.OpSetText:
+		op.Node.Element.Set("textContent", op.Node.Text)
+
+	case diff.OpSetAttribute:
+		r.setProp(op.Node, op.Name, op.Value)
+
+	case diff.OpRemoveAttribute:
+		r.removeProp(op.Node, op.Name, op.Value)
+
+	case diff.OpSetStyle:
+		r.updateStyle(op.Old, op.Node)
+
+	case diff.OpSetListener:
+		r.setListener(op.Node.Element, op.Name, op.Handler)
+
+	case diff.OpRemoveListener:
+		r.removeListener(op.Node.Element, op.Name)
+
+	case diff.OpUpdateComponent:
+		r.queueUpdate(op.Old, op.Node)
+
+	case diff.OpBind:
+		r.bind(op.Node)
+	}
+}
+
+// parentElement returns the DOM parent of the children of the parent node
+// The children of a fragment live in the DOM parent of the fragment, those of a portal in its target
+func (r *Renderer) parentElement(root js.Value, parent *vdom.VNode) js.Value {
+	if parent == nil {
+		return root
+	}
+	switch parent.Type {
+	case vdom.VNodeFragment:
+		return parent.Element.Get("parentNode")
+	case vdom.VNodePortal:
+		return r.portalTarget(parent)
+	}
+	return parent.Element
+}
+
+// portalTarget returns the DOM target of a portal: the element of its Target ID, or document.body
+// A target missing from the document falls back to document.body, reported in dev mode
+func (r *Renderer) portalTarget(portal *vdom.VNode) js.Value {
+	document := js.Global().Get("document")
+	if portal.Target == "" {
+		return document.Get("body")
+	}
+	target := document.Call("getElementById", portal.Target)
+	if !target.Truthy() {
+		if r.devMode {
+			fmt.Printf("WARNING: the portal target #%s does not exist, its children go to document.body\n", portal.Target)
+		}
+		return document.Get("body")
+	}
+	return target
+}
+
+// anchorElement returns the DOM node before which a child of parent is inserted
+// Without anchor the child goes at the end: of the element, or of the fragment
+func anchorElement(parent *vdom.VNode, anchor *vdom.VNode) js.Value {
+	if host := anchor.Host(); host != nil {
+		return host.Element
+	}
+	if parent != nil && parent.Type == vdom.VNodeFragment {
+		return parent.EndElement
+	}
+	return js.Null()
+}
+
+// domNodes returns the top level DOM nodes of a vnode, in order
+// A fragment spans from its opening to its closing marker
+func domNodes(vnode *vdom.VNode) []js.Value {
+	host := vnode.Host()
+	if host == nil {
+		return nil
+	}
+	if host.Type != vdom.VNodeFragment {
+		return []js.Value{host.Element}
+	}
+
+	nodes := []js.Value{}
+	for node := host.Element; node.Truthy(); node = node.Get("nextSibling") {
+		nodes = append(nodes, node)
+		if node.Equal(host.EndElement) {
+			break
+		}
+	}
+	return nodes
+}
+
+// removeDomNodes removes the top level DOM nodes of a vnode from their parent
+// The parent is the one of each DOM node: the target of a portal may have changed since its children were inserted
+func removeDomNodes(vnode *vdom.VNode) {
+	for _, domNode := range domNodes(vnode) {
+		if parent := domNode.Get("parentNode"); parent.Truthy() {
+			parent.Call("removeChild", domNode)
+		}
+	}
+}
+
+// removePortals removes from their targets the children of the portals of a removed subtree
+// They are not under the removed DOM nodes, so they would otherwise stay in the page
+func (r *Renderer) removePortals(vnode *vdom.VNode) {
+	if vnode == nil {
+		return
+	}
+	if vnode.Type == vdom.VNodeComponent {
+		r.removePortals(vnode.Rendered)
+		return
+	}
+	for _, child := range vnode.Children {
+		if vnode.Type == vdom.VNodePortal {
+			removeDomNodes(child)
+		}
+		r.removePortals(child)
+	}
+}
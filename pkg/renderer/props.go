@@ -0,0 +1,85 @@
+//go:build js && wasm
+
+package renderer
+
+import (
+	"strings"
+
+	"github.com/AureClai/vortex/pkg/vdom"
+)
+
+// setProp writes a prop of the vnode to its element, according to its kind (see vdom.PropKindOf)
+// It is used both when the element is created and when it is patched
+// A bound prop is written with its current value, its changes are followed apart (see bindings.go)
+func (r *Renderer) setProp(vnode *vdom.VNode, name string, value interface{}) {
+	element := vnode.Element
+	value = vdom.ResolveProp(value)
+
+	switch vdom.PropKindOf(name, value) {
+	case vdom.PropStyle:
+		element.Get("style").Set("cssText", vdom.FormatProp(value))
+
+	case vdom.PropClass:
+		element.Set("className", classWithStyle(vdom.FormatProp(value), vnode))
+
+	case vdom.PropProperty:
+		// Only touch the DOM when it differs, so the caret of an input does not jump
+		if _, isBool := value.(bool); isBool {
+			if element.Get(name).Truthy() != vdom.PropBool(value) {
+				element.Set(name, vdom.PropBool(value))
+			}
+			return
+		}
+		if text := vdom.FormatProp(value); element.Get(name).String() != text {
+			element.Set(name, text)
+		}
+
+	case vdom.PropBoolean:
+		if vdom.PropBool(value) {
+			element.Call("setAttribute", name, "")
+		} else {
+			element.Call("removeAttribute", name)
+		}
+
+	default:
+		if value == nil {
+			element.Call("removeAttribute", name)
+			return
+		}
+		element.Call("setAttribute", name, vdom.FormatProp(value))
+	}
+}
+
+// removeProp removes a prop that no longer exists from the element of the vnode
+func (r *Renderer) removeProp(vnode *vdom.VNode, name string, oldValue interface{}) {
+	element := vnode.Element
+	oldValue = vdom.ResolveProp(oldValue)
+
+	switch vdom.PropKindOf(name, oldValue) {
+	case vdom.PropStyle:
+		element.Get("style").Set("cssText", "")
+
+	case vdom.PropClass:
+		element.Set("className", classWithStyle("", vnode))
+
+	case vdom.PropProperty:
+		if _, isBool := oldValue.(bool); isBool {
+			element.Set(name, false)
+		} else {
+			element.Set(name, "")
+		}
+		element.Call("removeAttribute", name)
+
+	default:
+		element.Call("removeAttribute", name)
+	}
+}
+
+// classWithStyle appends the class of the applied style to the classes of the prop
+// Without it, writing the class prop would drop the class of the style
+func classWithStyle(classes string, vnode *vdom.VNode) string {
+	if vnode.AppliedStyle == nil {
+		return classes
+	}
+	return strings.TrimSpace(classes + " " + vnode.AppliedStyle.GetClassName())
+}
@@ -0,0 +1,107 @@
+//go:build js && wasm
+
+package renderer
+
+import (
+	"fmt"
+	"reflect"
+	"syscall/js"
+
+	"github.com/AureClai/vortex/pkg/diff"
+	"github.com/AureClai/vortex/pkg/vdom"
+)
+
+// scheduler batches the component updates requested during a frame
+// All the updates are flushed together in a single requestAnimationFrame callback
+type scheduler struct {
+	dirty   map[vdom.Component]bool // Components waiting to be re-rendered
+	pending bool                    // A frame has been requested and not flushed yet
+	flushFn js.Func                 // requestAnimationFrame callback, created once
+}
+
+// Mount renders the root component into the container
+// The component, like every component added with AddChild, can then be re-rendered with ScheduleUpdate
+func (r *Renderer) Mount(root vdom.Component) {
+	r.Render(vdom.NewComponentVNode(root, nil))
+}
+
+// ScheduleUpdate marks the component as dirty and schedules a flush on the next frame
+// Several updates of the same frame are merged into one patch of the dirty subtrees
+//
+// The component must be a pointer: it is found again in the tree by identity. A component
+// held by value is a new copy on every render of its parent, it is updated with its parent.
+func (r *Renderer) ScheduleUpdate(c vdom.Component) {
+	if !isPointer(c) {
+		panic(fmt.Sprintf("renderer: ScheduleUpdate needs a pointer to the component, got %T", c))
+	}
+	if r.scheduler.dirty == nil {
+		r.scheduler.dirty = make(map[vdom.Component]bool)
+	}
+	r.scheduler.dirty[c] = true
+
+	if r.scheduler.pending {
+		return
+	}
+	if r.scheduler.flushFn.IsUndefined() {
+		r.scheduler.flushFn = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+			r.flush()
+			return nil
+		})
+	}
+	r.scheduler.pending = true
+	js.Global().Call("requestAnimationFrame", r.scheduler.flushFn)
+}
+
+// flush re-renders the dirty components
+// Updates scheduled while flushing are handled on the next frame
+func (r *Renderer) flush() {
+	dirty := r.scheduler.dirty
+	r.scheduler.dirty = nil
+	r.scheduler.pending = false
+
+	if r.currentVNode == nil || len(dirty) == 0 {
+		return
+	}
+	r.renderDirty(r.container, r.currentVNode, dirty)
+}
+
+// isPointer reports whether the component is held by pointer
+// Only those can be keys of the dirty set: a struct holding a slice or a map is not comparable
+func isPointer(c vdom.Component) bool {
+	return reflect.ValueOf(c).Kind() == reflect.Pointer
+}
+
+// renderDirty walks the tree from the top and re-renders the first dirty component of each branch
+// The descendants of a re-rendered component are rendered again with it, they are not visited
+// parent is the DOM parent of the node: component nodes are transparent in the DOM
+func (r *Renderer) renderDirty(parent js.Value, node *vdom.VNode, dirty map[vdom.Component]bool) {
+	if node == nil {
+		return
+	}
+
+	switch node.Type {
+	case vdom.VNodeComponent:
+		if isPointer(node.Component) && dirty[node.Component] {
+			r.applyOps(parent, diff.Rerender(node))
+			return
+		}
+		r.renderDirty(parent, node.Rendered, dirty)
+
+	case vdom.VNodeElement:
+		for _, child := range node.Children {
+			r.renderDirty(node.Element, child, dirty)
+		}
+
+	case vdom.VNodeFragment:
+		// The children of a fragment live in the DOM parent of the fragment
+		for _, child := range node.Children {
+			r.renderDirty(parent, child, dirty)
+		}
+
+	case vdom.VNodePortal:
+		target := r.portalTarget(node)
+		for _, child := range node.Children {
+			r.renderDirty(target, child, dirty)
+		}
+	}
+}
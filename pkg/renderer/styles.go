@@ -0,0 +1,355 @@
+//go:build js && wasm
+
+package renderer
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"syscall/js"
+
+	"github.com/AureClai/vortex/pkg/style"
+	"github.com/AureClai/vortex/pkg/vdom"
+)
+
+// styleElementID is the id of the <style> balise holding the injected styles
+// The markup rendered on the server comes with its own (see the ssr package), it is reused
+const styleElementID = "vortex-styles"
+
+// staticCSSLinkID is the id of the <link> to the CSS extracted by `vortex build`
+const staticCSSLinkID = "vortex-static-css"
+
+// styleSheet is the single place where the CSS of the applied styles is injected
+//
+// The rules are inserted one by one with the CSSOM (CSSStyleSheet.insertRule), so the browser
+// only parses the new rules instead of the whole sheet. The inserts requested during a patch
+// are batched and flushed once the patch is applied, before the frame is painted.
+//
+// The classes are reference counted by the nodes using them. A class no longer used by any node
+// is removed on the next frame, unless a node uses it again in the meantime.
+//
+// The classes of the static file generated by `vortex build` are never injected nor removed.
+type styleSheet struct {
+	element js.Value        // The <style> balise
+	static  map[string]bool // Classes of the static CSS file
+
+	rules   map[string][]js.Value // Top level CSS rules of each injected class
+	refs    map[string]int        // Number of nodes using each class
+	pending []pendingStyle        // Styles waiting to be inserted, in order
+	queued  map[string]bool       // Classes of the pending styles
+
+	unused         map[string]bool // Classes no longer used, removed on the next frame
+	cleanupPending bool            // A frame has been requested for the removal
+	cleanupFn      js.Func         // requestAnimationFrame callback, created once
+}
+
+// pendingStyle is the CSS of a class, computed when the class is acquired
+// The style may change before the flush: its class and CSS then no longer match the queued ones
+type pendingStyle struct {
+	className string
+	css       string
+}
+
+// newStyleSheet returns the style sheet of the page, creating its <style> balise if needed
+func newStyleSheet() styleSheet {
+	document := js.Global().Get("document")
+	element := document.Call("getElementById", styleElementID)
+	if !element.Truthy() {
+		element = document.Call("createElement", "style")
+		element.Set("id", styleElementID)
+		document.Get("head").Call("appendChild", element)
+	}
+
+	s := styleSheet{
+		element: element,
+		static:  make(map[string]bool),
+		rules:   make(map[string][]js.Value),
+		refs:    make(map[string]int),
+		queued:  make(map[string]bool),
+		unused:  make(map[string]bool),
+	}
+	s.registerStatic(document.Call("getElementById", staticCSSLinkID))
+	return s
+}
+
+// registerStatic records the classes of the static CSS file linked by the page
+// When the file is not loaded yet its classes are simply injected like the others
+func (s *styleSheet) registerStatic(link js.Value) {
+	defer func() {
+		if recover() != nil {
+			fmt.Println("WARNING: the static CSS file cannot be read, its styles will be injected")
+		}
+	}()
+
+	if !link.Truthy() || !link.Get("sheet").Truthy() {
+		return
+	}
+	rules := link.Get("sheet").Get("cssRules")
+	for i := 0; i < rules.Length(); i++ {
+		for _, className := range ruleClasses(rules.Index(i)) {
+			s.static[className] = true
+		}
+	}
+}
+
+// acquire records a node using the style and returns its class name
+// The CSS of a class not injected yet is queued for the next flush
+func (s *styleSheet) acquire(st *style.Style) string {
+	className := st.GetClassName()
+	if s.static[className] {
+		return className
+	}
+	s.refs[className]++
+	delete(s.unused, className)
+
+	if _, injected := s.rules[className]; !injected && !s.queued[className] {
+		s.queued[className] = true
+		s.pending = append(s.pending, pendingStyle{className: className, css: st.ToCSS()})
+	}
+	return className
+}
+
+// release records a node no longer using the class
+// The class is removed on the next frame if no other node uses it by then
+func (s *styleSheet) release(className string) {
+	if s.static[className] || s.refs[className] == 0 {
+		return
+	}
+	s.refs[className]--
+	if s.refs[className] > 0 {
+		return
+	}
+
+	s.unused[className] = true
+	if s.cleanupPending {
+		return
+	}
+	if s.cleanupFn.IsUndefined() {
+		s.cleanupFn = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+			s.cleanup()
+			return nil
+		})
+	}
+	s.cleanupPending = true
+	js.Global().Call("requestAnimationFrame", s.cleanupFn)
+}
+
+// flush inserts the rules of the pending styles, under the class they were acquired with
+func (s *styleSheet) flush() {
+	if len(s.pending) == 0 {
+		return
+	}
+
+	sheet := s.element.Get("sheet")
+	for _, p := range s.pending {
+		delete(s.queued, p.className)
+		if _, injected := s.rules[p.className]; injected {
+			continue
+		}
+
+		inserted := []js.Value{}
+		for _, rule := range splitRules(p.css) {
+			if cssRule, ok := insertRule(sheet, rule); ok {
+				inserted = append(inserted, cssRule)
+			} else {
+				fmt.Printf("WARNING: the browser rejected the CSS rule %q\n", rule)
+			}
+		}
+		s.rules[p.className] = inserted
+	}
+	s.pending = nil
+}
+
+// cleanup removes the rules of the classes still unused
+func (s *styleSheet) cleanup() {
+	s.cleanupPending = false
+
+	removed := []js.Value{}
+	for className := range s.unused {
+		if s.refs[className] == 0 {
+			removed = append(removed, s.rules[className]...)
+			delete(s.rules, className)
+			delete(s.refs, className)
+		}
+	}
+	s.unused = make(map[string]bool)
+	if len(removed) == 0 {
+		return
+	}
+
+	// Walk backwards so the indexes of the remaining rules do not move
+	sheet := s.element.Get("sheet")
+	rules := sheet.Get("cssRules")
+	for i := rules.Length() - 1; i >= 0 && len(removed) > 0; i-- {
+		rule := rules.Index(i)
+		for j, r := range removed {
+			if rule.Equal(r) {
+				sheet.Call("deleteRule", i)
+				removed = append(removed[:j], removed[j+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// register records the classes defined in the <style> balise as already injected
+// It is used to take over the styles rendered on the server
+func (s *styleSheet) register() {
+	sheet := s.element.Get("sheet")
+	if !sheet.Truthy() {
+		return
+	}
+	rules := sheet.Get("cssRules")
+	for i := 0; i < rules.Length(); i++ {
+		rule := rules.Index(i)
+		for _, className := range ruleClasses(rule) {
+			s.rules[className] = append(s.rules[className], rule)
+		}
+	}
+}
+
+// styleClassPattern matches the classes generated by the style package in a selector
+var styleClassPattern = regexp.MustCompile(`\.(vtx-[A-Za-z0-9_-]+)`)
+
+// ruleClasses returns the generated classes a CSS rule applies to
+// Media queries hold their own rules, they are searched too
+func ruleClasses(rule js.Value) []string {
+	classes := []string{}
+	if selector := rule.Get("selectorText"); selector.Truthy() {
+		for _, match := range styleClassPattern.FindAllStringSubmatch(selector.String(), -1) {
+			classes = append(classes, match[1])
+		}
+	}
+	if nested := rule.Get("cssRules"); nested.Truthy() {
+		for i := 0; i < nested.Length(); i++ {
+			classes = append(classes, ruleClasses(nested.Index(i))...)
+		}
+	}
+	return classes
+}
+
+// insertRule inserts a rule at the end of the sheet and returns it
+// The browser throws on a rule it cannot parse, it is reported as not inserted
+func insertRule(sheet js.Value, rule string) (cssRule js.Value, ok bool) {
+	defer func() {
+		if recover() != nil {
+			ok = false
+		}
+	}()
+
+	rules := sheet.Get("cssRules")
+	index := rules.Length()
+	sheet.Call("insertRule", rule, index)
+	return rules.Index(index), true
+}
+
+// splitRules splits the CSS of a style into its top level rules
+// The braces of the strings (content: "{") and of the comments are not counted
+func splitRules(css string) []string {
+	rules := []string{}
+	depth, start := 0, 0
+	for i := 0; i < len(css); i++ {
+		switch c := css[i]; {
+		case c == '"' || c == '\'':
+			i = skipString(css, i)
+		case c == '/' && strings.HasPrefix(css[i:], "/*"):
+			if end := strings.Index(css[i+2:], "*/"); end >= 0 {
+				i += end + 3
+			} else {
+				i = len(css)
+			}
+			// A comment between two rules belongs to none
+			if depth == 0 {
+				start = i + 1
+			}
+		case c == '{':
+			depth++
+		case c == '}':
+			depth--
+			if depth == 0 {
+				rules = append(rules, strings.TrimSpace(css[start:i+1]))
+				start = i + 1
+			}
+		}
+	}
+	return rules
+}
+
+// skipString returns the index of the quote closing the string opened at start
+// The escaped characters, such as \", are skipped
+func skipString(css string, start int) int {
+	quote := css[start]
+	for i := start + 1; i < len(css); i++ {
+		switch css[i] {
+		case '\\':
+			i++
+		case quote:
+			return i
+		}
+	}
+	return len(css)
+}
+
+// processStyle applies the style of a new element: its CSS is injected and its class added
+func (r *Renderer) processStyle(vnode *vdom.VNode) {
+	if vnode.Type != vdom.VNodeElement {
+		fmt.Printf("WARNING: processStyle called on a non-element vnode %v\n", vnode.Type)
+	}
+
+	// If the style is nil, we return
+	if vnode.AppliedStyle == nil {
+		return
+	}
+
+	// Ajoute la class à l'élément, sans effacer celles du prop "class"
+	vnode.StyleClass = r.styles.acquire(vnode.AppliedStyle)
+	vnode.Element.Get("classList").Call("add", vnode.StyleClass)
+}
+
+// updateStyle switches the element from the class applied for the old node to the style of the new one
+// The class applied is compared, not the style: the same style may have been updated since
+func (r *Renderer) updateStyle(oldVNode, newVNode *vdom.VNode) {
+	newStyle := newVNode.AppliedStyle
+	classList := newVNode.Element.Get("classList")
+
+	newClass := ""
+	if newStyle != nil {
+		newClass = newStyle.GetClassName()
+	}
+
+	// Si le style n'a pas changé, on ne fait rien.
+	if oldVNode.StyleClass == newClass {
+		newVNode.StyleClass = newClass
+		return
+	}
+
+	// Retirer l'ancienne classe de style si elle existait
+	if oldVNode.StyleClass != "" {
+		classList.Call("remove", oldVNode.StyleClass)
+		r.styles.release(oldVNode.StyleClass)
+	}
+
+	// Ajouter la nouvelle classe de style si elle existe
+	newVNode.StyleClass = ""
+	if newStyle != nil {
+		newVNode.StyleClass = r.styles.acquire(newStyle)
+		classList.Call("add", newVNode.StyleClass)
+	}
+}
+
+// releaseStyles releases the styles of a removed subtree
+func (r *Renderer) releaseStyles(vnode *vdom.VNode) {
+	if vnode == nil {
+		return
+	}
+	if vnode.Type == vdom.VNodeComponent {
+		r.releaseStyles(vnode.Rendered)
+		return
+	}
+	if vnode.StyleClass != "" {
+		r.styles.release(vnode.StyleClass)
+	}
+	for _, child := range vnode.Children {
+		r.releaseStyles(child)
+	}
+}
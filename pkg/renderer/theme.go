@@ -0,0 +1,46 @@
+//go:build js && wasm
+
+package renderer
+
+import (
+	"syscall/js"
+
+	"github.com/AureClai/vortex/pkg/style"
+)
+
+// themeAttribute is the attribute of the root element holding the name of the theme
+const themeAttribute = "data-vtx-theme"
+
+// SetTheme applies the theme to the whole page
+// Its custom properties are written on the root element: the styles referencing the tokens
+// (see style.ThemeColor) follow at once, no class is generated or injected again
+//
+// Usage examples :
+//
+//	r.SetTheme(style.ThemeLight)
+//	toggle := func() { r.SetTheme(style.ThemeDark) }
+func (r *Renderer) SetTheme(theme *style.Theme) {
+	root := js.Global().Get("document").Get("documentElement")
+	rootStyle := root.Get("style")
+
+	// Remove the tokens the new theme does not define
+	vars := theme.Variables()
+	if r.theme != nil {
+		for name := range r.theme.Variables() {
+			if _, exists := vars[name]; !exists {
+				rootStyle.Call("removeProperty", name)
+			}
+		}
+	}
+
+	for name, value := range vars {
+		rootStyle.Call("setProperty", name, value)
+	}
+	root.Call("setAttribute", themeAttribute, theme.Name)
+	r.theme = theme
+}
+
+// Theme returns the theme applied with SetTheme, nil if none
+func (r *Renderer) Theme() *style.Theme {
+	return r.theme
+}
@@ -0,0 +1,23 @@
+//go:build js && wasm
+
+package router
+
+import "github.com/AureClai/vortex/pkg/vdom"
+
+// interceptClick prevents the default navigation of a click on a link and reports it
+// The clicks meant to open the link elsewhere are left to the browser
+func interceptClick(event vdom.Event) bool {
+	if event.Get("defaultPrevented").Bool() || event.Get("button").Int() != 0 {
+		return false
+	}
+	for _, key := range []string{"metaKey", "ctrlKey", "shiftKey", "altKey"} {
+		if event.Get(key).Bool() {
+			return false
+		}
+	}
+	if target := event.Get("currentTarget").Call("getAttribute", "target"); !target.IsNull() && target.String() != "_self" {
+		return false
+	}
+	event.Call("preventDefault")
+	return true
+}
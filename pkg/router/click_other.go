@@ -0,0 +1,10 @@
+//go:build !(js && wasm)
+
+package router
+
+import "github.com/AureClai/vortex/pkg/vdom"
+
+// interceptClick never intercepts outside the browser, no click is dispatched there
+func interceptClick(event vdom.Event) bool {
+	return false
+}
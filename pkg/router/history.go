@@ -0,0 +1,76 @@
+package router
+
+// History is where the router reads and writes the current location
+// The browser implementations are NewBrowserHistory (History API) and NewHashHistory
+// (location in the hash); NewMemoryHistory keeps the entries in memory, outside the browser
+type History interface {
+	// Location returns the current location, relative to the root of the application
+	Location() string
+	// Push adds a new entry for the location
+	Push(href string)
+	// Replace replaces the current entry by the location
+	Replace(href string)
+	// Go moves delta entries back (negative) or forward in the entries
+	Go(delta int)
+	// Listen calls listener when the current entry changes outside Push and Replace,
+	// such as with the back button, with the number of entries moved (negative backwards)
+	// The delta is 0 when the history cannot tell. It returns the function removing the listener
+	Listen(listener func(href string, delta int)) (stop func())
+	// Href returns the href of a link to the location
+	Href(href string) string
+}
+
+// MemoryHistory is a History keeping its entries in memory
+// It is used to render a route on the server and to test the navigation
+type MemoryHistory struct {
+	entries   []string
+	index     int
+	listeners map[int]func(href string, delta int)
+	nextID    int
+}
+
+// NewMemoryHistory creates a history holding the single entry
+func NewMemoryHistory(initial string) *MemoryHistory {
+	return &MemoryHistory{
+		entries:   []string{initial},
+		listeners: make(map[int]func(href string, delta int)),
+	}
+}
+
+func (h *MemoryHistory) Location() string {
+	return h.entries[h.index]
+}
+
+// Push drops the entries after the current one, like a browser
+func (h *MemoryHistory) Push(href string) {
+	h.entries = append(h.entries[:h.index+1], href)
+	h.index++
+}
+
+func (h *MemoryHistory) Replace(href string) {
+	h.entries[h.index] = href
+}
+
+func (h *MemoryHistory) Go(delta int) {
+	index := h.index + delta
+	if index < 0 || index >= len(h.entries) || delta == 0 {
+		return
+	}
+	h.index = index
+	for _, listener := range h.listeners {
+		listener(h.entries[h.index], delta)
+	}
+}
+
+func (h *MemoryHistory) Listen(listener func(href string, delta int)) func() {
+	id := h.nextID
+	h.nextID++
+	h.listeners[id] = listener
+	return func() {
+		delete(h.listeners, id)
+	}
+}
+
+func (h *MemoryHistory) Href(href string) string {
+	return href
+}
@@ -0,0 +1,112 @@
+//go:build js && wasm
+
+package router
+
+import (
+	"strings"
+	"syscall/js"
+)
+
+// stateIndexKey is the key of the index of the entry in the state of the entries
+// The popstate event only gives the state of the entry reached: the index tells how far it is
+const stateIndexKey = "vortexIndex"
+
+// browserHistory is the History of the browser window
+// In hash mode the location is kept in the hash ("#/users/42"), so the server only serves the root
+type browserHistory struct {
+	hash  bool
+	index int // Index of the current entry, kept in the state of each entry
+}
+
+// NewBrowserHistory returns the history of the window, using the History API
+// The server must serve the application for every path of the routes
+func NewBrowserHistory() History {
+	return newBrowserHistory(false)
+}
+
+// NewHashHistory returns the history of the window, keeping the location in the hash
+func NewHashHistory() History {
+	return newBrowserHistory(true)
+}
+
+// newBrowserHistory reads the index of the current entry, numbering it 0 the first time
+// The index survives a reload of the page, with the state of the entry
+func newBrowserHistory(hash bool) *browserHistory {
+	h := &browserHistory{hash: hash}
+	if index, ok := stateIndex(js.Global().Get("history").Get("state")); ok {
+		h.index = index
+	} else {
+		js.Global().Get("history").Call("replaceState", h.state(), "")
+	}
+	return h
+}
+
+// state returns the state of the current entry
+func (h *browserHistory) state() interface{} {
+	return map[string]interface{}{stateIndexKey: h.index}
+}
+
+// stateIndex returns the index held by the state of an entry
+func stateIndex(state js.Value) (int, bool) {
+	if state.Type() != js.TypeObject {
+		return 0, false
+	}
+	index := state.Get(stateIndexKey)
+	if index.Type() != js.TypeNumber {
+		return 0, false
+	}
+	return index.Int(), true
+}
+
+func (h *browserHistory) Location() string {
+	location := js.Global().Get("location")
+	if h.hash {
+		return "/" + strings.TrimLeft(strings.TrimPrefix(location.Get("hash").String(), "#"), "/")
+	}
+	return location.Get("pathname").String() + location.Get("search").String() + location.Get("hash").String()
+}
+
+func (h *browserHistory) Push(href string) {
+	h.index++
+	js.Global().Get("history").Call("pushState", h.state(), "", h.Href(href))
+}
+
+func (h *browserHistory) Replace(href string) {
+	js.Global().Get("history").Call("replaceState", h.state(), "", h.Href(href))
+}
+
+func (h *browserHistory) Go(delta int) {
+	js.Global().Get("history").Call("go", delta)
+}
+
+// Listen follows the popstate events, dispatched by the back and forward buttons
+// and, in hash mode, by the changes of the hash
+// An entry without index was added outside the history, such as by a link to a hash: it is
+// numbered as the entry following the current one, and the delta is unknown
+func (h *browserHistory) Listen(listener func(href string, delta int)) func() {
+	callback := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		delta := 0
+		if index, ok := stateIndex(args[0].Get("state")); ok {
+			delta = index - h.index
+			h.index = index
+		} else {
+			h.index++
+			js.Global().Get("history").Call("replaceState", h.state(), "")
+		}
+		listener(h.Location(), delta)
+		return nil
+	})
+	window := js.Global().Get("window")
+	window.Call("addEventListener", "popstate", callback)
+	return func() {
+		window.Call("removeEventListener", "popstate", callback)
+		callback.Release()
+	}
+}
+
+func (h *browserHistory) Href(href string) string {
+	if h.hash {
+		return "#" + href
+	}
+	return href
+}
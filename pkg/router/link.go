@@ -0,0 +1,45 @@
+package router
+
+import (
+	"github.com/AureClai/vortex/pkg/vdom"
+)
+
+// Link is an <a> navigating with the router instead of loading the page
+// The clicks opening a new tab or window (modifier keys, middle button, target) are left to the browser
+type Link struct {
+	vdom.ComponentBase
+	router *Router
+	to     string
+}
+
+// Link creates a link to the location, holding the children
+// The link of the current location, or of one of its ancestors, gets aria-current="page"
+//
+// Usage examples :
+//
+//	appRouter.Link("/users/42", &vdom.VNode{Type: vdom.VNodeText, Text: "Profile"})
+func (r *Router) Link(to string, children ...*vdom.VNode) *Link {
+	link := &Link{
+		ComponentBase: vdom.NewComponentBase("a"),
+		router:        r,
+		to:            to,
+	}
+	link.AddChildren(children...)
+	link.On("click", func(event vdom.Event) {
+		if interceptClick(event) {
+			r.Navigate(to)
+		}
+	})
+	return link
+}
+
+// Render writes the href for the history mode and the active state
+func (l *Link) Render() *vdom.VNode {
+	l.SetProp("href", l.router.Href(l.to))
+	if l.router.IsActive(l.to) {
+		l.SetProp("aria-current", "page")
+	} else {
+		l.SetProp("aria-current", nil)
+	}
+	return l.ComponentBase.Render()
+}
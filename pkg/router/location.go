@@ -0,0 +1,44 @@
+package router
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Location is a location of the application, relative to its root
+type Location struct {
+	Path  string
+	Query url.Values
+	Hash  string // Without the "#"
+}
+
+// ParseLocation parses a location such as "/users/42?tab=posts#bio"
+// The path always starts with "/"
+func ParseLocation(href string) Location {
+	location := Location{Query: url.Values{}}
+
+	if i := strings.Index(href, "#"); i >= 0 {
+		location.Hash = href[i+1:]
+		href = href[:i]
+	}
+	if i := strings.Index(href, "?"); i >= 0 {
+		if query, err := url.ParseQuery(href[i+1:]); err == nil {
+			location.Query = query
+		}
+		href = href[:i]
+	}
+	location.Path = "/" + strings.TrimLeft(href, "/")
+	return location
+}
+
+// String returns the location as written in a link
+func (l Location) String() string {
+	href := l.Path
+	if len(l.Query) > 0 {
+		href += "?" + l.Query.Encode()
+	}
+	if l.Hash != "" {
+		href += "#" + l.Hash
+	}
+	return href
+}
@@ -0,0 +1,18 @@
+//go:build js && wasm
+
+package router
+
+import (
+	"github.com/AureClai/vortex/pkg/renderer"
+)
+
+// Mount renders the routes into the renderer and re-renders them after every navigation
+// The re-render is scheduled like any component update, so it happens on the next frame
+func (r *Router) Mount(rend *renderer.Renderer) {
+	view := r.Component()
+	r.OnChange(func(Location) {
+		rend.ScheduleUpdate(view)
+	})
+	r.Start()
+	rend.Mount(view)
+}
@@ -0,0 +1,173 @@
+package router
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Params are the values of the parameters of the matched path, by name
+// A wildcard is stored under its name, or "*" when it has none
+type Params map[string]string
+
+// segmentKind is the kind of a segment of a route path
+type segmentKind int
+
+const (
+	segmentStatic   segmentKind = iota // "users"
+	segmentParam                       // ":id"
+	segmentWildcard                    // "*" or "*path", matches the rest of the path
+)
+
+type segment struct {
+	kind  segmentKind
+	value string // The text of a static segment, the name of a parameter or a wildcard
+}
+
+// pattern is a compiled route path
+type pattern []segment
+
+// compilePattern compiles a route path such as "/users/:id/*rest"
+func compilePattern(path string) pattern {
+	p := pattern{}
+	for _, part := range splitPath(path) {
+		switch {
+		case strings.HasPrefix(part, ":"):
+			p = append(p, segment{kind: segmentParam, value: part[1:]})
+		case strings.HasPrefix(part, "*"):
+			name := part[1:]
+			if name == "" {
+				name = "*"
+			}
+			p = append(p, segment{kind: segmentWildcard, value: name})
+		default:
+			p = append(p, segment{kind: segmentStatic, value: part})
+		}
+	}
+	return p
+}
+
+// match matches the pattern against the start of the path segments
+// It returns the parameters and the number of segments consumed, a wildcard consumes them all
+func (p pattern) match(segments []string, params Params) (int, bool) {
+	for i, seg := range p {
+		switch seg.kind {
+		case segmentWildcard:
+			params[seg.value] = unescape(strings.Join(segments[min(i, len(segments)):], "/"))
+			return len(segments), true
+		case segmentParam:
+			if i >= len(segments) {
+				return 0, false
+			}
+			params[seg.value] = unescape(segments[i])
+		case segmentStatic:
+			if i >= len(segments) || segments[i] != seg.value {
+				return 0, false
+			}
+		}
+	}
+	return len(p), true
+}
+
+// compare orders two patterns by specificity, the more specific first:
+// segment by segment, a static segment wins over a parameter which wins over a wildcard,
+// then the longer pattern wins, unless it only adds a wildcard
+func (p pattern) compare(other pattern) int {
+	n := min(len(p), len(other))
+	for i := 0; i < n; i++ {
+		if p[i].kind != other[i].kind {
+			return int(p[i].kind) - int(other[i].kind)
+		}
+	}
+	if len(p) > n && p[n].kind == segmentWildcard {
+		return 1
+	}
+	if len(other) > n && other[n].kind == segmentWildcard {
+		return -1
+	}
+	return len(other) - len(p)
+}
+
+// Match is a route matched by a path, from the outermost layout to the page
+type Match struct {
+	Route  *Route
+	Params Params // Parameters of the whole path, the parents' included
+	Path   string // Part of the path matched by the route and its parents
+}
+
+// Resolve returns the chain of routes matching the path, from the outermost layout to the page,
+// or nil when no route matches
+// The routes of a level are tried from the most specific to the least (see compare),
+// a route with children only matches when one of its children matches the rest of the path
+func Resolve(routes []*Route, path string) []Match {
+	location := ParseLocation(path)
+	return resolve(routes, splitPath(location.Path), nil, Params{})
+}
+
+// resolve matches the routes against the segments left, matched being the segments
+// consumed by the parents
+func resolve(routes []*Route, segments, matched []string, params Params) []Match {
+	for _, route := range sortRoutes(routes) {
+		routeParams := copyParams(params)
+		n, ok := route.pattern.match(segments, routeParams)
+		if !ok {
+			continue
+		}
+		routeMatched := append(matched[:len(matched):len(matched)], segments[:n]...)
+		match := Match{
+			Route:  route,
+			Params: routeParams,
+			Path:   "/" + strings.Join(routeMatched, "/"),
+		}
+
+		if len(route.Children) > 0 {
+			if chain := resolve(route.Children, segments[n:], routeMatched, routeParams); chain != nil {
+				return append([]Match{match}, chain...)
+			}
+			continue
+		}
+		if n == len(segments) {
+			return []Match{match}
+		}
+	}
+	return nil
+}
+
+// sortRoutes returns the routes sorted by specificity, keeping the declaration order otherwise
+func sortRoutes(routes []*Route) []*Route {
+	sorted := make([]*Route, len(routes))
+	copy(sorted, routes)
+	for _, route := range sorted {
+		route.compile()
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].pattern.compare(sorted[j].pattern) < 0
+	})
+	return sorted
+}
+
+// splitPath returns the non empty segments of a path
+func splitPath(path string) []string {
+	segments := []string{}
+	for _, part := range strings.Split(path, "/") {
+		if part != "" {
+			segments = append(segments, part)
+		}
+	}
+	return segments
+}
+
+func unescape(segment string) string {
+	if value, err := url.PathUnescape(segment); err == nil {
+		return value
+	}
+	return segment
+}
+
+func copyParams(params Params) Params {
+	copied := make(Params, len(params))
+	for name, value := range params {
+		copied[name] = value
+	}
+	return copied
+}
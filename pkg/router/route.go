@@ -0,0 +1,57 @@
+package router
+
+import (
+	"sync"
+
+	"github.com/AureClai/vortex/pkg/vdom"
+)
+
+// View renders the page or the layout of a route
+// outlet is the node rendered by the matched child route, nil for a page
+type View func(params Params, outlet *vdom.VNode) *vdom.VNode
+
+// Guard decides whether the navigation to a location may happen
+// It returns ok to let it happen, otherwise a path to redirect to, or "" to cancel it
+//
+// Usage examples :
+//
+//	func requireLogin(to, from router.Location) (string, bool) {
+//	    if !session.LoggedIn() {
+//	        return "/login?next=" + url.QueryEscape(to.String()), false
+//	    }
+//	    return "", true
+//	}
+type Guard func(to, from Location) (redirect string, ok bool)
+
+// Route maps a path pattern to a view
+//
+// The path is made of static segments, parameters (":id") and a final wildcard ("*" or "*name")
+// matching the rest of the path. The path of a child is relative to its parent: a route with
+// children is a layout rendering the matched child in its outlet, an empty path being the index.
+//
+// Usage examples :
+//
+//	routes := []*router.Route{
+//	    {Path: "/", View: home},
+//	    {Path: "/users", View: usersLayout, Children: []*router.Route{
+//	        {Path: "", View: userList},
+//	        {Path: ":id", View: userDetail, Guard: requireLogin},
+//	    }},
+//	    {Path: "/files/*path", View: fileBrowser},
+//	}
+type Route struct {
+	Path     string
+	View     View     // nil renders the outlet as is
+	Children []*Route // Nested routes rendered in the outlet of View
+	Guard    Guard    // Checked before entering the route or one of its children
+
+	once    sync.Once
+	pattern pattern
+}
+
+// compile compiles the path of the route, once
+func (r *Route) compile() {
+	r.once.Do(func() {
+		r.pattern = compilePattern(r.Path)
+	})
+}
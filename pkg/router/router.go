@@ -0,0 +1,255 @@
+// Package router maps the location of the page to the views of the application.
+//
+// The routes form a tree: a route with children is a layout rendering the matched child in its
+// outlet (see Route). The matching is pure Go (see Resolve), so the routes can be rendered on the
+// server with a MemoryHistory and tested outside the browser.
+//
+// Basic Usage:
+//
+//	r := renderer.NewRenderer("app")
+//	appRouter := router.New(router.NewBrowserHistory(), routes...)
+//	appRouter.NotFound = notFoundView
+//	appRouter.Mount(r)
+//
+// The location changes through Navigate, the Link component and the back and forward buttons of
+// the browser (popstate). Each change runs the guards, then re-renders the routes through the renderer.
+package router
+
+import (
+	"log"
+	"strings"
+
+	"github.com/AureClai/vortex/pkg/vdom"
+)
+
+// maxRedirects stops the guards redirecting to each other forever
+const maxRedirects = 10
+
+// Router holds the routes and the current location
+type Router struct {
+	NotFound View // Rendered when no route matches, nothing when nil
+
+	routes      []*Route
+	history     History
+	guards      []Guard
+	current     Location
+	matches     []Match
+	listeners   []func(Location)
+	stopHistory func()
+	undoDelta   int // Delta of the move undoing a cancelled popstate, its popstate is ignored
+}
+
+// New creates a router reading and writing the location in the history
+// Start (or Mount in the browser) resolves the current location and follows its changes
+func New(history History, routes ...*Route) *Router {
+	return &Router{
+		routes:  routes,
+		history: history,
+		current: ParseLocation("/"),
+	}
+}
+
+// BeforeEach adds a guard checked before every navigation, before the guards of the routes
+func (r *Router) BeforeEach(guard Guard) {
+	r.guards = append(r.guards, guard)
+}
+
+// OnChange adds a listener called after every navigation
+func (r *Router) OnChange(listener func(Location)) {
+	r.listeners = append(r.listeners, listener)
+}
+
+// Start resolves the current location of the history and follows its changes
+func (r *Router) Start() {
+	r.transition(r.history.Location(), navigationInitial)
+	if r.stopHistory == nil {
+		r.stopHistory = r.history.Listen(func(href string, delta int) {
+			if undo := r.undoDelta; undo != 0 {
+				r.undoDelta = 0
+				if delta == undo {
+					return
+				}
+			}
+			if !r.transition(href, navigationPop) {
+				r.restore(delta)
+			}
+		})
+	}
+}
+
+// restore puts the history back on the current location after a cancelled popstate
+// The history already moved by delta entries, it moves back so the entries are kept as they were.
+// When the delta is unknown, the entry reached is replaced by the current location instead
+func (r *Router) restore(delta int) {
+	if delta == 0 {
+		r.history.Replace(r.current.String())
+		return
+	}
+	r.undoDelta = -delta
+	r.history.Go(-delta)
+}
+
+// Stop stops following the changes of the history
+func (r *Router) Stop() {
+	if r.stopHistory != nil {
+		r.stopHistory()
+		r.stopHistory = nil
+		r.undoDelta = 0
+	}
+}
+
+// Navigate goes to the location, adding an entry to the history
+// It reports whether the navigation happened, a guard may have cancelled it
+func (r *Router) Navigate(href string) bool {
+	return r.transition(href, navigationPush)
+}
+
+// Replace goes to the location, replacing the current entry of the history
+func (r *Router) Replace(href string) bool {
+	return r.transition(href, navigationReplace)
+}
+
+// Back goes to the previous entry of the history
+func (r *Router) Back() {
+	r.history.Go(-1)
+}
+
+// Forward goes to the next entry of the history
+func (r *Router) Forward() {
+	r.history.Go(1)
+}
+
+// Location returns the current location
+func (r *Router) Location() Location {
+	return r.current
+}
+
+// Matches returns the routes matched by the current location, from the outermost layout to the page
+func (r *Router) Matches() []Match {
+	return r.matches
+}
+
+// Params returns the parameters of the current location
+func (r *Router) Params() Params {
+	if len(r.matches) == 0 {
+		return Params{}
+	}
+	return r.matches[len(r.matches)-1].Params
+}
+
+// Href returns the href of a link to the location, according to the history mode
+func (r *Router) Href(href string) string {
+	return r.history.Href(href)
+}
+
+// IsActive reports whether the current location is the path or one of its descendants
+func (r *Router) IsActive(href string) bool {
+	path := ParseLocation(href).Path
+	if path == "/" {
+		return r.current.Path == "/"
+	}
+	return r.current.Path == path || strings.HasPrefix(r.current.Path, path+"/")
+}
+
+// View renders the matched routes: the page, in the outlet of its layouts
+func (r *Router) View() *vdom.VNode {
+	if len(r.matches) == 0 {
+		if r.NotFound != nil {
+			return r.NotFound(Params{}, nil)
+		}
+		return nil
+	}
+
+	params := r.Params()
+	var outlet *vdom.VNode
+	for i := len(r.matches) - 1; i >= 0; i-- {
+		if view := r.matches[i].Route.View; view != nil {
+			outlet = view(params, outlet)
+		}
+	}
+	return outlet
+}
+
+// Component returns the component rendering the matched routes
+// It is re-rendered on every navigation by Mount
+func (r *Router) Component() vdom.Component {
+	return &routerView{router: r}
+}
+
+// routerView renders the view of the router
+type routerView struct {
+	router *Router
+}
+
+func (v *routerView) Render() *vdom.VNode {
+	if node := v.router.View(); node != nil {
+		return node
+	}
+	return vdom.NewFragment()
+}
+
+type navigation int
+
+const (
+	navigationInitial navigation = iota
+	navigationPush
+	navigationReplace
+	navigationPop
+)
+
+// transition runs the guards and goes to the location, or to where they redirect
+func (r *Router) transition(href string, kind navigation) bool {
+	from := r.current
+	to := ParseLocation(href)
+	matches := Resolve(r.routes, to.Path)
+
+	for redirects := 0; ; redirects++ {
+		redirect, ok := r.check(to, from, matches)
+		if ok {
+			break
+		}
+		if redirect == "" || redirects == maxRedirects {
+			if redirects == maxRedirects {
+				log.Printf("router: the navigation to %q was redirected more than %d times, it is cancelled", href, maxRedirects)
+			}
+			return false
+		}
+		to = ParseLocation(redirect)
+		matches = Resolve(r.routes, to.Path)
+		if kind != navigationPush {
+			kind = navigationReplace
+		}
+	}
+
+	switch kind {
+	case navigationPush:
+		r.history.Push(to.String())
+	case navigationReplace:
+		r.history.Replace(to.String())
+	}
+
+	r.current = to
+	r.matches = matches
+	for _, listener := range r.listeners {
+		listener(to)
+	}
+	return true
+}
+
+// check runs the global guards then the guards of the matched routes, from the outermost
+func (r *Router) check(to, from Location, matches []Match) (string, bool) {
+	for _, guard := range r.guards {
+		if redirect, ok := guard(to, from); !ok {
+			return redirect, false
+		}
+	}
+	for _, match := range matches {
+		if match.Route.Guard == nil {
+			continue
+		}
+		if redirect, ok := match.Route.Guard(to, from); !ok {
+			return redirect, false
+		}
+	}
+	return "", true
+}
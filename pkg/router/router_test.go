@@ -0,0 +1,341 @@
+package router
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/AureClai/vortex/pkg/vdom"
+)
+
+// view returns a view rendering an element with the tag, holding the outlet
+func view(tag string) View {
+	return func(params Params, outlet *vdom.VNode) *vdom.VNode {
+		node := &vdom.VNode{Type: vdom.VNodeElement, Tag: tag}
+		if outlet != nil {
+			node.Children = []*vdom.VNode{outlet}
+		}
+		return node
+	}
+}
+
+// matchedPaths returns the route paths of the matches, from the outermost
+func matchedPaths(matches []Match) []string {
+	paths := []string{}
+	for _, match := range matches {
+		paths = append(paths, match.Route.Path)
+	}
+	return paths
+}
+
+func TestResolveParams(t *testing.T) {
+	routes := []*Route{
+		{Path: "/users/:id/posts/:post"},
+	}
+
+	matches := Resolve(routes, "/users/42/posts/hello%20world?tab=1")
+	if len(matches) != 1 {
+		t.Fatalf("got %d matches, want 1", len(matches))
+	}
+	want := Params{"id": "42", "post": "hello world"}
+	if !reflect.DeepEqual(matches[0].Params, want) {
+		t.Errorf("params = %v, want %v", matches[0].Params, want)
+	}
+	if matches[0].Path != "/users/42/posts/hello%20world" {
+		t.Errorf("path = %q", matches[0].Path)
+	}
+
+	if matches := Resolve(routes, "/users/42/posts"); matches != nil {
+		t.Errorf("matched a missing parameter: %v", matchedPaths(matches))
+	}
+	if matches := Resolve(routes, "/users/42/posts/1/extra"); matches != nil {
+		t.Errorf("matched extra segments: %v", matchedPaths(matches))
+	}
+}
+
+func TestResolveWildcards(t *testing.T) {
+	routes := []*Route{
+		{Path: "/files/*path"},
+		{Path: "/*"},
+	}
+
+	tests := []struct {
+		path   string
+		route  string
+		params Params
+	}{
+		{"/files/a/b/c.txt", "/files/*path", Params{"path": "a/b/c.txt"}},
+		{"/files", "/files/*path", Params{"path": ""}},
+		{"/docs/intro", "/*", Params{"*": "docs/intro"}},
+		{"/", "/*", Params{"*": ""}},
+	}
+	for _, tt := range tests {
+		matches := Resolve(routes, tt.path)
+		if len(matches) != 1 || matches[0].Route.Path != tt.route {
+			t.Errorf("%s: matched %v, want %s", tt.path, matchedPaths(matches), tt.route)
+			continue
+		}
+		if !reflect.DeepEqual(matches[0].Params, tt.params) {
+			t.Errorf("%s: params = %v, want %v", tt.path, matches[0].Params, tt.params)
+		}
+	}
+}
+
+func TestResolveSpecificity(t *testing.T) {
+	// Declared from the least specific to the most
+	routes := []*Route{
+		{Path: "/*"},
+		{Path: "/users/*rest"},
+		{Path: "/users/:id"},
+		{Path: "/users/:id/edit"},
+		{Path: "/users/new"},
+		{Path: "/users"},
+	}
+
+	tests := map[string]string{
+		"/users":         "/users",
+		"/users/new":     "/users/new",
+		"/users/42":      "/users/:id",
+		"/users/42/edit": "/users/:id/edit",
+		"/users/42/x":    "/users/*rest",
+		"/about":         "/*",
+	}
+	for path, want := range tests {
+		matches := Resolve(routes, path)
+		if len(matches) != 1 || matches[0].Route.Path != want {
+			t.Errorf("%s: matched %v, want %s", path, matchedPaths(matches), want)
+		}
+	}
+}
+
+func TestResolveNestedLayouts(t *testing.T) {
+	routes := []*Route{
+		{Path: "/", View: view("home")},
+		{Path: "/users", View: view("layout"), Children: []*Route{
+			{Path: "", View: view("list")},
+			{Path: ":id", View: view("detail")},
+			{Path: ":id/settings", Children: []*Route{
+				{Path: "*section", View: view("settings")},
+			}},
+		}},
+	}
+
+	tests := []struct {
+		path  string
+		chain []string
+		last  Match
+	}{
+		{"/users", []string{"/users", ""}, Match{Path: "/users", Params: Params{}}},
+		{"/users/7", []string{"/users", ":id"}, Match{Path: "/users/7", Params: Params{"id": "7"}}},
+		{"/users/7/settings/privacy", []string{"/users", ":id/settings", "*section"},
+			Match{Path: "/users/7/settings/privacy", Params: Params{"id": "7", "section": "privacy"}}},
+	}
+	for _, tt := range tests {
+		matches := Resolve(routes, tt.path)
+		if got := matchedPaths(matches); !reflect.DeepEqual(got, tt.chain) {
+			t.Errorf("%s: matched %v, want %v", tt.path, got, tt.chain)
+			continue
+		}
+		last := matches[len(matches)-1]
+		if last.Path != tt.last.Path || !reflect.DeepEqual(last.Params, tt.last.Params) {
+			t.Errorf("%s: last match %q %v, want %q %v", tt.path, last.Path, last.Params, tt.last.Path, tt.last.Params)
+		}
+	}
+
+	// A layout only matches when one of its children matches the rest
+	if matches := Resolve(routes, "/users/7/unknown"); matches != nil {
+		t.Errorf("matched %v, want no match", matchedPaths(matches))
+	}
+
+	r := New(NewMemoryHistory("/users/7"), routes...)
+	r.Start()
+	node := r.View()
+	if node == nil || node.Tag != "layout" || len(node.Children) != 1 || node.Children[0].Tag != "detail" {
+		t.Fatalf("view = %+v, want the detail in the outlet of the layout", node)
+	}
+
+	// A route without view renders the outlet as is
+	r.Navigate("/users/7/settings/privacy")
+	node = r.View()
+	if node == nil || node.Tag != "layout" || len(node.Children) != 1 || node.Children[0].Tag != "settings" {
+		t.Fatalf("view = %+v, want the settings in the outlet of the layout", node)
+	}
+}
+
+func TestNotFound(t *testing.T) {
+	r := New(NewMemoryHistory("/missing"), &Route{Path: "/", View: view("home")})
+	r.Start()
+	if node := r.View(); node != nil {
+		t.Fatalf("view = %+v without NotFound, want nil", node)
+	}
+	r.NotFound = view("not-found")
+	if node := r.View(); node == nil || node.Tag != "not-found" {
+		t.Fatalf("view = %+v, want the NotFound view", node)
+	}
+}
+
+func TestGuardRedirect(t *testing.T) {
+	history := NewMemoryHistory("/")
+	r := New(history,
+		&Route{Path: "/"},
+		&Route{Path: "/login"},
+		&Route{Path: "/admin", Guard: func(to, from Location) (string, bool) {
+			return "/login?next=" + to.Path, false
+		}},
+	)
+	r.Start()
+
+	if !r.Navigate("/admin") {
+		t.Fatal("the redirected navigation did not happen")
+	}
+	if got := r.Location().String(); got != "/login?next=%2Fadmin" {
+		t.Errorf("location = %q, want the login page", got)
+	}
+	if got := history.Location(); got != "/login?next=%2Fadmin" {
+		t.Errorf("history location = %q, want the login page", got)
+	}
+	if len(history.entries) != 2 {
+		t.Errorf("history entries = %v, want the redirect pushed once", history.entries)
+	}
+}
+
+func TestGuardCancel(t *testing.T) {
+	history := NewMemoryHistory("/")
+	r := New(history, &Route{Path: "/"}, &Route{Path: "/admin"})
+	r.BeforeEach(func(to, from Location) (string, bool) {
+		return "", to.Path != "/admin"
+	})
+	r.Start()
+
+	if r.Navigate("/admin") {
+		t.Fatal("the cancelled navigation happened")
+	}
+	if r.Location().Path != "/" || history.Location() != "/" || len(history.entries) != 1 {
+		t.Errorf("location = %q, history = %v, want nothing changed", r.Location().Path, history.entries)
+	}
+}
+
+func TestGuardRedirectLoop(t *testing.T) {
+	history := NewMemoryHistory("/")
+	calls := 0
+	r := New(history,
+		&Route{Path: "/"},
+		&Route{Path: "/a", Guard: func(to, from Location) (string, bool) {
+			calls++
+			return "/b", false
+		}},
+		&Route{Path: "/b", Guard: func(to, from Location) (string, bool) {
+			calls++
+			return "/a", false
+		}},
+	)
+	r.Start()
+
+	if r.Navigate("/a") {
+		t.Fatal("the navigation redirected forever happened")
+	}
+	if calls != maxRedirects+1 {
+		t.Errorf("the guards ran %d times, want %d", calls, maxRedirects+1)
+	}
+	if r.Location().Path != "/" || history.Location() != "/" {
+		t.Errorf("location = %q, history = %q, want nothing changed", r.Location().Path, history.Location())
+	}
+}
+
+func TestLocationRoundTrip(t *testing.T) {
+	tests := []struct {
+		href string
+		want string
+	}{
+		{"/", "/"},
+		{"", "/"},
+		{"users", "/users"},
+		{"//users/42", "/users/42"},
+		{"/users/42?tab=posts#bio", "/users/42?tab=posts#bio"},
+		{"/search?q=a+b&lang=fr", "/search?lang=fr&q=a+b"},
+		{"/tags?t=go&t=wasm", "/tags?t=go&t=wasm"},
+		{"/page#", "/page"},
+	}
+	for _, tt := range tests {
+		location := ParseLocation(tt.href)
+		if got := location.String(); got != tt.want {
+			t.Errorf("ParseLocation(%q).String() = %q, want %q", tt.href, got, tt.want)
+		}
+		if again := ParseLocation(location.String()); !reflect.DeepEqual(again, location) {
+			t.Errorf("%q: %+v after a round trip, want %+v", tt.href, again, location)
+		}
+	}
+
+	location := ParseLocation("/search?q=a+b#top")
+	if location.Path != "/search" || location.Query.Get("q") != "a b" || location.Hash != "top" {
+		t.Errorf("ParseLocation = %+v", location)
+	}
+}
+
+func TestPopstate(t *testing.T) {
+	history := NewMemoryHistory("/")
+	r := New(history, &Route{Path: "/"}, &Route{Path: "/editor"})
+	changes := []string{}
+	r.OnChange(func(location Location) {
+		changes = append(changes, location.Path)
+	})
+	r.Start()
+	r.Navigate("/editor")
+	r.Back()
+
+	if r.Location().Path != "/" {
+		t.Fatalf("location = %q after Back, want /", r.Location().Path)
+	}
+	r.Forward()
+	if r.Location().Path != "/editor" {
+		t.Fatalf("location = %q after Forward, want /editor", r.Location().Path)
+	}
+	if want := []string{"/", "/editor", "/", "/editor"}; !reflect.DeepEqual(changes, want) {
+		t.Errorf("changes = %v, want %v", changes, want)
+	}
+}
+
+func TestPopstateCancelled(t *testing.T) {
+	history := NewMemoryHistory("/")
+	r := New(history, &Route{Path: "/"}, &Route{Path: "/settings"}, &Route{Path: "/editor"})
+	// Unsaved changes: the editor cannot be left
+	saved := false
+	r.BeforeEach(func(to, from Location) (string, bool) {
+		return "", saved || from.Path != "/editor"
+	})
+	r.Start()
+	r.Navigate("/settings")
+	r.Navigate("/editor")
+
+	changes := 0
+	r.OnChange(func(Location) { changes++ })
+	wantEntries := []string{"/", "/settings", "/editor"}
+
+	for _, delta := range []int{-1, -2} {
+		history.Go(delta)
+
+		if r.Location().Path != "/editor" {
+			t.Errorf("Go(%d): location = %q, want the editor kept", delta, r.Location().Path)
+		}
+		// The history moved before the guards ran, it moved back without rewriting the entries
+		if history.index != 2 || !reflect.DeepEqual(history.entries, wantEntries) {
+			t.Errorf("Go(%d): history at %d of %v, want 2 of %v", delta, history.index, history.entries, wantEntries)
+		}
+		if changes != 0 {
+			t.Errorf("Go(%d): OnChange called %d times for a cancelled navigation", delta, changes)
+		}
+	}
+
+	// The move back was not taken for a navigation: the next one goes through
+	saved = true
+	r.Back()
+	if r.Location().Path != "/settings" || history.index != 1 || changes != 1 {
+		t.Errorf("location = %q at %d after %d changes, want /settings at 1 after 1", r.Location().Path, history.index, changes)
+	}
+	r.Forward()
+
+	r.Stop()
+	history.Go(-2)
+	if r.Location().Path != "/editor" || changes != 2 {
+		t.Errorf("the router followed the history after Stop")
+	}
+}
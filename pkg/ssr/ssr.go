@@ -0,0 +1,375 @@
+// Package ssr renders Vortex virtual DOM trees to HTML outside of the browser.
+//
+// The server sends the markup and the styles of the first render, so the page is
+// painted (and indexed) before the wasm module is downloaded.
+//
+// Basic Usage:
+//
+//	http.HandleFunc("/", func(w http.ResponseWriter, req *http.Request) {
+//	    app := NewApp()
+//	    ssr.Render(w, vdom.NewComponentVNode(app, nil))
+//	})
+//
+// Render writes a <style id="vortex-styles"> element holding the CSS of every applied
+// style of the tree, followed by the markup. Stylesheet and WriteMarkup write them
+// separately, when the styles belong to the <head> of the page.
+//
+// Component nodes are rendered along the way, their Rendered subtree is kept as in the browser.
+// Fragments are written between their comment markers (see vdom.FragmentStartMarker), and an
+// empty comment separates adjacent text nodes, so the markup keeps the structure of the tree.
+// Portals only write their placeholder: their target is outside the rendered markup, the children
+// are created in the browser when the markup is hydrated.
+package ssr
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/AureClai/vortex/pkg/style"
+	"github.com/AureClai/vortex/pkg/vdom"
+)
+
+// StyleElementID is the id of the <style> element written by Render
+// It is the one the renderer injects its styles into in the browser
+const StyleElementID = "vortex-styles"
+
+// TextSeparator is the comment written between two adjacent text nodes
+// Without it the browser would parse them as a single text node
+const TextSeparator = ""
+
+// rawTextElements hold text the parser does not decode, only their closing tag ends them
+var rawTextElements = map[string]bool{
+	"script": true,
+	"style":  true,
+}
+
+// voidElements have no content and no closing tag
+var voidElements = map[string]bool{
+	"area":   true,
+	"base":   true,
+	"br":     true,
+	"col":    true,
+	"embed":  true,
+	"hr":     true,
+	"img":    true,
+	"input":  true,
+	"link":   true,
+	"meta":   true,
+	"source": true,
+	"track":  true,
+	"wbr":    true,
+}
+
+// Render writes the styles and the markup of the tree to w
+// The markup is streamed while the tree is walked
+func Render(w io.Writer, node *vdom.VNode) error {
+	expand(node)
+
+	sw := &writer{w: w}
+	sw.writeString(`<style id="` + StyleElementID + `">`)
+	sw.writeString(Stylesheet(node))
+	sw.writeString("</style>")
+	sw.writeNode(node)
+	return sw.err
+}
+
+// RenderToString returns the styles and the markup of the tree
+func RenderToString(node *vdom.VNode) (string, error) {
+	var sb strings.Builder
+	if err := Render(&sb, node); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// WriteMarkup writes only the markup of the tree to w
+func WriteMarkup(w io.Writer, node *vdom.VNode) error {
+	expand(node)
+
+	sw := &writer{w: w}
+	sw.writeNode(node)
+	return sw.err
+}
+
+// Stylesheet returns the CSS of every style applied in the tree, each style once
+// The styles come in the order of the tree. The CSS can be written in a <style> element as is:
+// a "</style" it holds, such as in content: "</style>", is escaped so it cannot close the element
+func Stylesheet(node *vdom.VNode) string {
+	expand(node)
+
+	var sb strings.Builder
+	seen := make(map[string]bool)
+	for _, s := range AppliedStyles(node) {
+		className := s.GetClassName()
+		if seen[className] {
+			continue
+		}
+		seen[className] = true
+		sb.WriteString(s.ToCSS())
+	}
+	return escapeRawText(sb.String(), "style")
+}
+
+// escapeRawText escapes the closing tags of the raw text element in its text, whatever their case
+// "</style" becomes "<\/style": the same text for CSS and JavaScript strings, but no closing tag
+func escapeRawText(text, tag string) string {
+	closing := "</" + tag
+	lower := asciiLower(text) // Same length as text, unlike strings.ToLower
+	if !strings.Contains(lower, closing) {
+		return text
+	}
+
+	var sb strings.Builder
+	for {
+		i := strings.Index(lower, closing)
+		if i < 0 {
+			break
+		}
+		sb.WriteString(text[:i+1] + `\`)
+		text, lower = text[i+1:], lower[i+1:]
+	}
+	sb.WriteString(text)
+	return sb.String()
+}
+
+// asciiLower returns the text with its ASCII letters in lower case, byte for byte
+func asciiLower(text string) string {
+	lower := []byte(text)
+	for i, c := range lower {
+		if 'A' <= c && c <= 'Z' {
+			lower[i] = c + 'a' - 'A'
+		}
+	}
+	return string(lower)
+}
+
+// AppliedStyles returns the styles applied in the tree, in the order of the tree
+// The component nodes must have been rendered
+func AppliedStyles(node *vdom.VNode) []*style.Style {
+	styles := []*style.Style{}
+	var walk func(node *vdom.VNode)
+	walk = func(node *vdom.VNode) {
+		if node == nil {
+			return
+		}
+		if node.Type == vdom.VNodeComponent {
+			walk(node.Rendered)
+			return
+		}
+		if node.Type == vdom.VNodePortal {
+			return
+		}
+		if node.AppliedStyle != nil {
+			styles = append(styles, node.AppliedStyle)
+		}
+		for _, child := range node.Children {
+			walk(child)
+		}
+	}
+	walk(node)
+	return styles
+}
+
+// expand renders the component nodes of the tree which have not been rendered yet
+func expand(node *vdom.VNode) {
+	if node == nil {
+		return
+	}
+	if node.Type == vdom.VNodeComponent {
+		if node.Rendered == nil {
+			node.RenderComponent()
+		}
+		expand(node.Rendered)
+		return
+	}
+	for _, child := range node.Children {
+		expand(child)
+	}
+}
+
+// writer writes the markup and keeps the first error
+type writer struct {
+	w   io.Writer
+	err error
+}
+
+func (sw *writer) writeString(s string) {
+	if sw.err != nil {
+		return
+	}
+	_, sw.err = io.WriteString(sw.w, s)
+}
+
+func (sw *writer) writeNode(node *vdom.VNode) {
+	node = node.Host()
+	if node == nil || sw.err != nil {
+		return
+	}
+
+	switch node.Type {
+	case vdom.VNodeText:
+		sw.writeString(html.EscapeString(node.Text))
+
+	case vdom.VNodeFragment:
+		sw.writeString("<!--" + vdom.FragmentStartMarker + "-->")
+		sw.writeChildren(node.Children)
+		sw.writeString("<!--" + vdom.FragmentEndMarker + "-->")
+
+	case vdom.VNodePortal:
+		sw.writeString("<!--" + vdom.PortalMarker + "-->")
+
+	case vdom.VNodeElement:
+		if !validName(node.Tag, tagNameChars) {
+			sw.err = fmt.Errorf("ssr: invalid tag name %q", node.Tag)
+			return
+		}
+		sw.writeString("<" + node.Tag)
+		sw.writeAttributes(node)
+		sw.writeString(">")
+		if voidElements[node.Tag] {
+			return
+		}
+		if value, ok := textareaValue(node); ok {
+			sw.writeString(value)
+		} else if rawTextElements[node.Tag] {
+			sw.writeRawText(node)
+		} else {
+			sw.writeChildren(node.Children)
+		}
+		sw.writeString("</" + node.Tag + ">")
+	}
+}
+
+// textareaValue returns the escaped content of a textarea holding a value prop
+// The value of a textarea is its content, not an attribute. The parser drops a leading newline,
+// it is doubled so the value keeps it
+func textareaValue(node *vdom.VNode) (string, bool) {
+	if node.Tag != "textarea" {
+		return "", false
+	}
+	value, ok := node.Props["value"]
+	if !ok {
+		return "", false
+	}
+	text := vdom.FormatProp(vdom.ResolveProp(value))
+	if strings.HasPrefix(text, "\n") {
+		text = "\n" + text
+	}
+	return html.EscapeString(text), true
+}
+
+// Characters allowed in the tag names after the first letter, and in the attribute names
+const (
+	tagNameChars       = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-"
+	attributeNameChars = tagNameChars + "_:."
+)
+
+// validName reports whether the name starts with a letter and only holds the allowed characters
+// The names are written as is in the markup: anything else could inject markup
+func validName(name, allowed string) bool {
+	if name == "" || !strings.ContainsRune(tagNameChars[:52], rune(name[0])) {
+		return false
+	}
+	for _, c := range name {
+		if !strings.ContainsRune(allowed, c) {
+			return false
+		}
+	}
+	return true
+}
+
+// writeRawText writes the text children of a script or a style as is, without HTML escaping
+// Only their closing tag is escaped. The other children cannot be written in raw text, they are skipped
+func (sw *writer) writeRawText(node *vdom.VNode) {
+	for _, child := range node.Children {
+		if host := child.Host(); host != nil && host.Type == vdom.VNodeText {
+			sw.writeString(escapeRawText(host.Text, node.Tag))
+		}
+	}
+}
+
+// writeChildren writes the children, separating the adjacent text nodes
+func (sw *writer) writeChildren(children []*vdom.VNode) {
+	previousText := false
+	for _, child := range children {
+		host := child.Host()
+		if host == nil {
+			continue
+		}
+		isText := host.Type == vdom.VNodeText
+		if isText && previousText {
+			sw.writeString("<!--" + TextSeparator + "-->")
+		}
+		previousText = isText
+		sw.writeNode(host)
+	}
+}
+
+// writeAttributes writes the props of the element as attributes, in a stable order
+// The props are written as the renderer would write them (see vdom.PropKindOf),
+// the DOM properties are written as their initial attribute
+func (sw *writer) writeAttributes(node *vdom.VNode) {
+	_, hasClass := node.Props["class"]
+	if !hasClass && node.AppliedStyle != nil {
+		sw.writeAttribute("class", node.AppliedStyle.GetClassName())
+	}
+
+	for _, name := range sortedProps(node.Props) {
+		// Invalid names are skipped, the browser would refuse them too
+		if !validName(name, attributeNameChars) {
+			continue
+		}
+		if name == "value" && node.Tag == "textarea" {
+			continue
+		}
+		value := vdom.ResolveProp(node.Props[name])
+		switch vdom.PropKindOf(name, value) {
+		case vdom.PropClass:
+			sw.writeAttribute("class", classWithStyle(vdom.FormatProp(value), node))
+
+		case vdom.PropBoolean:
+			if vdom.PropBool(value) {
+				sw.writeString(" " + name)
+			}
+
+		case vdom.PropProperty:
+			if _, isBool := value.(bool); isBool {
+				if vdom.PropBool(value) {
+					sw.writeString(" " + name)
+				}
+				continue
+			}
+			sw.writeAttribute(name, vdom.FormatProp(value))
+
+		default:
+			if value != nil {
+				sw.writeAttribute(name, vdom.FormatProp(value))
+			}
+		}
+	}
+}
+
+func (sw *writer) writeAttribute(name, value string) {
+	sw.writeString(" " + name + `="` + html.EscapeString(value) + `"`)
+}
+
+// classWithStyle appends the class of the applied style to the classes of the prop
+func classWithStyle(classes string, node *vdom.VNode) string {
+	if node.AppliedStyle == nil {
+		return classes
+	}
+	return strings.TrimSpace(classes + " " + node.AppliedStyle.GetClassName())
+}
+
+// sortedProps returns the names of the props in alphabetical order
+func sortedProps(props map[string]interface{}) []string {
+	names := make([]string, 0, len(props))
+	for name := range props {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,165 @@
+package ssr
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/AureClai/vortex/pkg/style"
+	"github.com/AureClai/vortex/pkg/vdom"
+)
+
+func element(tag string, props map[string]interface{}, children ...*vdom.VNode) *vdom.VNode {
+	if props == nil {
+		props = map[string]interface{}{}
+	}
+	return &vdom.VNode{Type: vdom.VNodeElement, Tag: tag, Props: props, Children: children}
+}
+
+func text(content string) *vdom.VNode {
+	return &vdom.VNode{Type: vdom.VNodeText, Text: content}
+}
+
+func TestWriteMarkup(t *testing.T) {
+	tests := []struct {
+		name string
+		node *vdom.VNode
+		want string
+	}{
+		{"text escaped", element("p", nil, text(`<b>"Tom" & 'Jerry'</b>`)),
+			`<p>&lt;b&gt;&#34;Tom&#34; &amp; &#39;Jerry&#39;&lt;/b&gt;</p>`},
+		{"attribute escaped", element("a", map[string]interface{}{"title": `"><script>`}),
+			`<a title="&#34;&gt;&lt;script&gt;"></a>`},
+		{"adjacent texts separated", element("p", nil, text("a"), text("b"), element("br", nil), text("c")),
+			`<p>a<!---->b<br>c</p>`},
+		{"void element", element("input", map[string]interface{}{"type": "text"}, text("ignored")),
+			`<input type="text">`},
+		{"invalid attribute names skipped", element("div", map[string]interface{}{
+			`onclick="x"`: "1", "a b": "2", "1st": "3", "data-id": "4", "xml:lang": "fr",
+		}), `<div data-id="4" xml:lang="fr"></div>`},
+		{"textarea value as content", element("textarea", map[string]interface{}{"value": "\nline </textarea>", "rows": 2}),
+			"<textarea rows=\"2\">\n\nline &lt;/textarea&gt;</textarea>"},
+		{"textarea without value", element("textarea", nil, text("a < b")),
+			`<textarea>a &lt; b</textarea>`},
+		{"boolean attributes", element("button", map[string]interface{}{"disabled": true, "hidden": false, "autofocus": "false"}),
+			`<button disabled></button>`},
+		{"properties as initial attributes", element("input", map[string]interface{}{"checked": true, "value": 42, "muted": false}),
+			`<input checked value="42">`},
+		{"omitted props", element("div", map[string]interface{}{"id": nil, "title": "kept"}),
+			`<div title="kept"></div>`},
+		{"bound props", element("input", map[string]interface{}{
+			"placeholder": vdom.Bind(func() string { return "name" }),
+			"disabled":    vdom.Bind(func() bool { return true }),
+			"value":       vdom.Bind(func() string { return "ada" }),
+		}), `<input disabled placeholder="name" value="ada">`},
+		{"fragment markers", element("ul", nil, vdom.NewFragment(element("li", nil), element("li", nil))),
+			`<ul><!--[--><li></li><li></li><!--]--></ul>`},
+		{"portal placeholder only", element("div", nil, vdom.NewPortal("#modal", element("p", nil, text("hidden")))),
+			`<div><!--portal--></div>`},
+		{"nil children skipped", element("p", nil, nil, text("a"), nil, text("b")),
+			`<p>a<!---->b</p>`},
+		{"script text unescaped", element("script", nil, text(`if (a < b && c) { s = "</SCRIPT>" }`)),
+			`<script>if (a < b && c) { s = "<\/SCRIPT>" }</script>`},
+		{"style text unescaped", element("style", nil, text(`a > b { content: "&" } </style>`), element("p", nil)),
+			`<style>a > b { content: "&" } <\/style></style>`},
+	}
+	for _, tt := range tests {
+		var sb strings.Builder
+		if err := WriteMarkup(&sb, tt.node); err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if got := sb.String(); got != tt.want {
+			t.Errorf("%s:\n got %s\nwant %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestWriteMarkupRejectsInvalidTags(t *testing.T) {
+	for _, tag := range []string{"", "1h", "div onclick=x", "a>", "-x", "é"} {
+		var sb strings.Builder
+		if err := WriteMarkup(&sb, element("div", nil, element(tag, nil))); err == nil {
+			t.Errorf("tag %q: no error, wrote %s", tag, sb.String())
+		}
+	}
+}
+
+func TestValidName(t *testing.T) {
+	tests := []struct {
+		name    string
+		allowed string
+		want    bool
+	}{
+		{"div", tagNameChars, true},
+		{"my-element2", tagNameChars, true},
+		{"H1", tagNameChars, true},
+		{"xml:lang", tagNameChars, false},
+		{"xml:lang", attributeNameChars, true},
+		{"data_x.y", attributeNameChars, true},
+		{"", attributeNameChars, false},
+		{"-x", attributeNameChars, false},
+		{"_x", attributeNameChars, false},
+		{"a b", attributeNameChars, false},
+		{`a"`, attributeNameChars, false},
+		{"a=", attributeNameChars, false},
+		{"a/", attributeNameChars, false},
+		{"aé", attributeNameChars, false},
+	}
+	for _, tt := range tests {
+		if got := validName(tt.name, tt.allowed); got != tt.want {
+			t.Errorf("validName(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestTextareaValue(t *testing.T) {
+	tests := []struct {
+		node  *vdom.VNode
+		want  string
+		found bool
+	}{
+		{element("textarea", map[string]interface{}{"value": "a & b"}), "a &amp; b", true},
+		{element("textarea", map[string]interface{}{"value": "\ntext"}), "\n\ntext", true},
+		{element("textarea", map[string]interface{}{"value": vdom.Bind(func() int { return 3 })}), "3", true},
+		{element("textarea", nil), "", false},
+		{element("input", map[string]interface{}{"value": "a"}), "", false},
+	}
+	for _, tt := range tests {
+		value, found := textareaValue(tt.node)
+		if value != tt.want || found != tt.found {
+			t.Errorf("textareaValue(<%s %v>) = %q, %v, want %q, %v", tt.node.Tag, tt.node.Props, value, found, tt.want, tt.found)
+		}
+	}
+}
+
+func TestStylesheet(t *testing.T) {
+	card := style.New(
+		style.Opacity(0.5),
+		style.Query("@media (width < 600px)", style.Opacity(1)),
+		style.PseudoElement("before", style.Content("</STYLE><script>")),
+	)
+	tree := element("div", nil, element("section", nil), element("section", nil))
+	tree.Children[0].AppliedStyle = card
+	tree.Children[1].AppliedStyle = card
+
+	css := Stylesheet(tree)
+	if strings.Count(css, "."+card.GetClassName()+"::before") != 1 {
+		t.Errorf("the style is not written once:\n%s", css)
+	}
+	if !strings.Contains(css, "@media (width < 600px)") {
+		t.Errorf("the range media query is broken:\n%s", css)
+	}
+	if strings.Contains(strings.ToLower(css), "</style") || !strings.Contains(css, `<\/STYLE><script>`) {
+		t.Errorf("the closing tag is not escaped:\n%s", css)
+	}
+
+	markup, err := RenderToString(tree)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `<style id="` + StyleElementID + `">` + css + `</style><div>` +
+		`<section class="` + card.GetClassName() + `"></section>` +
+		`<section class="` + card.GetClassName() + `"></section></div>`
+	if markup != want {
+		t.Errorf("markup:\n got %s\nwant %s", markup, want)
+	}
+}
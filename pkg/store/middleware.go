@@ -0,0 +1,80 @@
+package store
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+)
+
+// Middleware wraps the dispatch of a store
+// It receives the store and the next dispatch of the chain, and returns its own dispatch which
+// may log, transform, delay or drop the actions before calling next.
+//
+// Usage examples :
+//
+//	// Drops the actions sent while the app is read-only
+//	readOnly := func(s *store.Store[AppState], next store.Dispatch) store.Dispatch {
+//	    return func(action store.Action) {
+//	        if !s.State().ReadOnly {
+//	            next(action)
+//	        }
+//	    }
+//	}
+type Middleware[S any] func(s *Store[S], next Dispatch) Dispatch
+
+// Logger logs every action with the time its dispatch took and the resulting state
+// logf defaults to log.Printf when nil
+func Logger[S any](logf func(format string, args ...interface{})) Middleware[S] {
+	if logf == nil {
+		logf = log.Printf
+	}
+	return func(s *Store[S], next Dispatch) Dispatch {
+		return func(action Action) {
+			start := time.Now()
+			next(action)
+			logf("store: %T %+v (%v) -> %+v", action, action, time.Since(start), s.State())
+		}
+	}
+}
+
+// Persist saves the state in JSON to the storage under the key after every dispatch
+// Restore loads it back when creating the store.
+//
+// The state saved is read once the dispatch returns: under concurrent dispatches it may already
+// hold the actions dispatched since, and the saves of the dispatches may land in any order.
+// Dispatch from a single goroutine, such as the event loop in the browser, to save the latest state.
+//
+// Usage examples :
+//
+//	storage := store.LocalStorage()
+//	prefs := store.New(reducePrefs, store.Restore(storage, "prefs", Prefs{Theme: "light"}),
+//	    store.Persist[Prefs](storage, "prefs"))
+func Persist[S any](storage Storage, key string) Middleware[S] {
+	return func(s *Store[S], next Dispatch) Dispatch {
+		return func(action Action) {
+			next(action)
+			encoded, err := json.Marshal(s.State())
+			if err != nil {
+				log.Printf("store: could not persist %q: %v", key, err)
+				return
+			}
+			storage.SetItem(key, string(encoded))
+		}
+	}
+}
+
+// Restore returns the state saved in the storage under the key, or initial when there is none
+// The saved JSON is decoded over initial, so the fields added since it was saved keep their
+// initial value.
+func Restore[S any](storage Storage, key string, initial S) S {
+	saved, ok := storage.GetItem(key)
+	if !ok {
+		return initial
+	}
+	state := initial
+	if err := json.Unmarshal([]byte(saved), &state); err != nil {
+		log.Printf("store: could not restore %q: %v", key, err)
+		return initial
+	}
+	return state
+}
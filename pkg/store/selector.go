@@ -0,0 +1,123 @@
+package store
+
+import (
+	"sync"
+)
+
+// Selector derives a value from the state, such as a slice of it or a computed list
+type Selector[S, T any] func(state S) T
+
+// Memo creates a selector computing its value only when its input changes
+// The input is compared with ==, the computed value is returned as is otherwise, so expensive
+// derivations (filtering, sorting, parsing) run once per change of what they depend on.
+//
+// Usage examples :
+//
+//	searchPattern := store.Memo(func(s AppState) string { return s.Query },
+//	    func(query string) *regexp.Regexp { return compileSearch(query) })
+func Memo[S any, I comparable, T any](input Selector[S, I], compute func(input I) T) Selector[S, T] {
+	return MemoFunc(input, func(a, b I) bool { return a == b }, compute)
+}
+
+// MemoFunc is Memo for the inputs compared with equal, such as slices with slices.Equal
+//
+// Usage examples :
+//
+//	visibleTodos := store.MemoFunc(func(s AppState) []Todo { return s.Todos },
+//	    slices.Equal[[]Todo], func(todos []Todo) []Todo { return filterDone(todos) })
+func MemoFunc[S, I, T any](input Selector[S, I], equal func(a, b I) bool, compute func(input I) T) Selector[S, T] {
+	var (
+		mutex    sync.Mutex
+		computed bool
+		last     I
+		value    T
+	)
+	return func(state S) T {
+		in := input(state)
+		mutex.Lock()
+		defer mutex.Unlock()
+		if !computed || !equal(in, last) {
+			value = compute(in)
+			last = in
+			computed = true
+		}
+		return value
+	}
+}
+
+// Connection holds the value a component selects from a store, and re-renders the component
+// when that value changes
+type Connection[S, T any] struct {
+	store       *Store[S]
+	selector    Selector[S, T]
+	equal       func(a, b T) bool
+	reRender    func()
+	mutex       sync.Mutex
+	value       T
+	unsubscribe func()
+}
+
+// Connect selects a comparable value from the store for a component
+// reRender is called after the dispatches changing the selected value, usually to schedule an
+// update of the component. The connection must be closed when the component is unmounted.
+//
+// Usage examples :
+//
+//	func NewCounter(r *renderer.Renderer, s *store.Store[AppState]) *Counter {
+//	    c := &Counter{ComponentBase: vdom.NewComponentBase("div")}
+//	    c.count = store.Connect(s, func(state AppState) int { return state.Count }, func() {
+//	        r.ScheduleUpdate(c)
+//	    })
+//	    return c
+//	}
+//
+//	func (c *Counter) OnUnmount() { c.count.Close() }
+//	func (c *Counter) Render() *vdom.VNode { ... c.count.Value() ... }
+func Connect[S any, T comparable](s *Store[S], selector Selector[S, T], reRender func()) *Connection[S, T] {
+	return ConnectFunc(s, selector, func(a, b T) bool { return a == b }, reRender)
+}
+
+// ConnectFunc is Connect for the values compared with equal, such as slices with slices.Equal
+func ConnectFunc[S, T any](s *Store[S], selector Selector[S, T], equal func(a, b T) bool, reRender func()) *Connection[S, T] {
+	c := &Connection[S, T]{
+		store:    s,
+		selector: selector,
+		equal:    equal,
+		reRender: reRender,
+	}
+	// Subscribe before selecting, so a dispatch in between is not missed
+	c.unsubscribe = s.Subscribe(c.update)
+	c.mutex.Lock()
+	c.value = selector(s.State())
+	c.mutex.Unlock()
+	return c
+}
+
+// Value returns the selected value
+func (c *Connection[S, T]) Value() T {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.value
+}
+
+// Dispatch dispatches an action to the store of the connection
+func (c *Connection[S, T]) Dispatch(action Action) {
+	c.store.Dispatch(action)
+}
+
+// Close stops following the store
+func (c *Connection[S, T]) Close() {
+	c.unsubscribe()
+}
+
+// update selects the value from the new state and re-renders the component if it changed
+func (c *Connection[S, T]) update(state S) {
+	value := c.selector(state)
+	c.mutex.Lock()
+	changed := !c.equal(c.value, value)
+	c.value = value
+	c.mutex.Unlock()
+	if changed && c.reRender != nil {
+		c.reRender()
+	}
+}
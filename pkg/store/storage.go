@@ -0,0 +1,35 @@
+package store
+
+import (
+	"sync"
+)
+
+// Storage is a key-value storage for persisted states, such as the localStorage of the browser
+type Storage interface {
+	GetItem(key string) (value string, ok bool)
+	SetItem(key, value string)
+}
+
+// MemoryStorage is a Storage kept in memory, for the server and the tests
+type MemoryStorage struct {
+	mutex sync.Mutex
+	items map[string]string
+}
+
+// NewMemoryStorage creates an empty memory storage
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{items: make(map[string]string)}
+}
+
+func (m *MemoryStorage) GetItem(key string) (string, bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	value, ok := m.items[key]
+	return value, ok
+}
+
+func (m *MemoryStorage) SetItem(key, value string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.items[key] = value
+}
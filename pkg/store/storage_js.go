@@ -0,0 +1,34 @@
+//go:build js && wasm
+
+package store
+
+import (
+	"syscall/js"
+)
+
+// webStorage is the localStorage or the sessionStorage of the browser
+type webStorage struct {
+	storage js.Value
+}
+
+// LocalStorage returns the localStorage of the browser, kept across the sessions
+func LocalStorage() Storage {
+	return webStorage{storage: js.Global().Get("localStorage")}
+}
+
+// SessionStorage returns the sessionStorage of the browser, cleared when the tab is closed
+func SessionStorage() Storage {
+	return webStorage{storage: js.Global().Get("sessionStorage")}
+}
+
+func (w webStorage) GetItem(key string) (string, bool) {
+	value := w.storage.Call("getItem", key)
+	if value.Type() != js.TypeString {
+		return "", false
+	}
+	return value.String(), true
+}
+
+func (w webStorage) SetItem(key, value string) {
+	w.storage.Call("setItem", key, value)
+}
@@ -0,0 +1,142 @@
+// Package store holds application state shared between components.
+//
+// A Store keeps a state of any type S, changed only by dispatching actions to its reducer.
+// Middleware wrap the dispatch (logging, persistence), selectors derive values from the state,
+// and the components Connect to the slice they render: they are re-rendered only when that
+// slice changes, instead of threading reRender closures between them.
+//
+// Basic Usage:
+//
+//	type AddTodo struct{ Text string }
+//
+//	todos := store.New(func(state AppState, action store.Action) AppState {
+//	    switch a := action.(type) {
+//	    case AddTodo:
+//	        state.Todos = append(slices.Clone(state.Todos), Todo{Text: a.Text})
+//	    }
+//	    return state
+//	}, AppState{}, store.Logger[AppState](nil))
+//
+//	todos.Dispatch(AddTodo{Text: "Write docs"})
+//
+// The package is pure Go: it runs the same in the browser, on the server and in tests.
+package store
+
+import (
+	"sort"
+	"sync"
+)
+
+// Action describes a change of the state, usually a struct per kind of change
+type Action interface{}
+
+// Reducer returns the state following the action
+// It must not modify the state it receives (copy the slices and maps it changes) nor dispatch.
+type Reducer[S any] func(state S, action Action) S
+
+// Dispatch sends an action to the store
+type Dispatch func(action Action)
+
+// Store holds a state changed by dispatching actions to its reducer
+// It can be used from several goroutines. The listeners are called after each dispatch, in the
+// order they subscribed, outside of the lock so they can read the state or dispatch again.
+//
+// The notifications never overlap and the listeners always end with the latest state: a dispatch
+// made while they run (by a listener or another goroutine) is delivered once they return,
+// by the dispatch already notifying. The intermediate states may then be skipped.
+type Store[S any] struct {
+	mutex     sync.Mutex
+	state     S
+	reducer   Reducer[S]
+	dispatch  Dispatch
+	listeners map[int]func(state S)
+	nextID    int
+	notifying bool // A dispatch is calling the listeners
+	pending   bool // The state changed since the listeners were last called with it
+}
+
+// New creates a store with the reducer and the initial state
+// The middleware wrap the dispatch in their order: the first one sees the action first.
+func New[S any](reducer Reducer[S], initial S, middleware ...Middleware[S]) *Store[S] {
+	s := &Store[S]{
+		state:     initial,
+		reducer:   reducer,
+		listeners: make(map[int]func(state S)),
+	}
+	s.dispatch = s.reduce
+	for i := len(middleware) - 1; i >= 0; i-- {
+		s.dispatch = middleware[i](s, s.dispatch)
+	}
+	return s
+}
+
+// State returns the current state
+func (s *Store[S]) State() S {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.state
+}
+
+// Dispatch sends the action through the middleware to the reducer, then notifies the listeners
+func (s *Store[S]) Dispatch(action Action) {
+	s.dispatch(action)
+}
+
+// Subscribe calls the listener with the state after every dispatch
+// It returns the function removing the listener.
+func (s *Store[S]) Subscribe(listener func(state S)) (unsubscribe func()) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	id := s.nextID
+	s.nextID++
+	s.listeners[id] = listener
+	return func() {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		delete(s.listeners, id)
+	}
+}
+
+// reduce is the end of the dispatch chain: it applies the reducer and notifies the listeners
+// unless another dispatch is notifying them, which then delivers the new state too
+// A panic of the reducer or of a listener leaves the store unlocked and not notifying
+func (s *Store[S]) reduce(action Action) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.state = s.reducer(s.state, action)
+	s.pending = true
+	if s.notifying {
+		return
+	}
+	s.notifying = true
+	defer func() { s.notifying = false }()
+
+	for s.pending {
+		s.pending = false
+		s.notify(s.sortedListeners(), s.state)
+	}
+}
+
+// notify calls the listeners with the state outside of the lock, the mutex being held
+// The mutex is held again when it returns, even when a listener panics
+func (s *Store[S]) notify(listeners []func(state S), state S) {
+	s.mutex.Unlock()
+	defer s.mutex.Lock()
+	for _, listener := range listeners {
+		listener(state)
+	}
+}
+
+// sortedListeners returns the listeners in the order they subscribed, the mutex being held
+func (s *Store[S]) sortedListeners() []func(state S) {
+	ids := make([]int, 0, len(s.listeners))
+	for id := range s.listeners {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	listeners := make([]func(state S), 0, len(ids))
+	for _, id := range ids {
+		listeners = append(listeners, s.listeners[id])
+	}
+	return listeners
+}
@@ -0,0 +1,275 @@
+package store
+
+import (
+	"reflect"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testState struct {
+	Count int
+	Name  string
+	Theme string `json:",omitempty"`
+}
+
+type increment struct{}
+
+type rename struct{ Name string }
+
+func reduceTest(state testState, action Action) testState {
+	switch a := action.(type) {
+	case increment:
+		state.Count++
+	case rename:
+		state.Name = a.Name
+	}
+	return state
+}
+
+func TestMiddlewareOrder(t *testing.T) {
+	var calls []string
+	record := func(name string) Middleware[testState] {
+		return func(s *Store[testState], next Dispatch) Dispatch {
+			return func(action Action) {
+				calls = append(calls, name+" before")
+				next(action)
+				calls = append(calls, name+" after")
+			}
+		}
+	}
+	s := New(func(state testState, action Action) testState {
+		calls = append(calls, "reduce")
+		return reduceTest(state, action)
+	}, testState{}, record("first"), record("second"))
+	s.Subscribe(func(testState) { calls = append(calls, "listener") })
+
+	s.Dispatch(increment{})
+
+	want := []string{"first before", "second before", "reduce", "listener", "second after", "first after"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+}
+
+func TestMiddlewareCanDropActions(t *testing.T) {
+	drop := func(s *Store[testState], next Dispatch) Dispatch {
+		return func(action Action) {
+			if _, ok := action.(rename); !ok {
+				next(action)
+			}
+		}
+	}
+	s := New(reduceTest, testState{}, drop)
+	s.Dispatch(rename{Name: "dropped"})
+	s.Dispatch(increment{})
+
+	if state := s.State(); state.Name != "" || state.Count != 1 {
+		t.Errorf("state = %+v, want the rename dropped", state)
+	}
+}
+
+func TestMemo(t *testing.T) {
+	computed := 0
+	label := Memo(func(s testState) int { return s.Count }, func(count int) string {
+		computed++
+		return string(rune('a' + count))
+	})
+
+	if got := label(testState{Count: 1}); got != "b" {
+		t.Errorf("label = %q, want b", got)
+	}
+	label(testState{Count: 1, Name: "other"})
+	if computed != 1 {
+		t.Errorf("computed %d times for the same input, want 1", computed)
+	}
+	if got := label(testState{Count: 2}); got != "c" || computed != 2 {
+		t.Errorf("label = %q after %d computations, want c after 2", got, computed)
+	}
+}
+
+func TestMemoFunc(t *testing.T) {
+	computed := 0
+	total := MemoFunc(func(s []int) []int { return s }, func(a, b []int) bool {
+		return reflect.DeepEqual(a, b)
+	}, func(values []int) int {
+		computed++
+		sum := 0
+		for _, v := range values {
+			sum += v
+		}
+		return sum
+	})
+
+	total([]int{1, 2})
+	total([]int{1, 2})
+	if got := total([]int{1, 2, 3}); got != 6 || computed != 2 {
+		t.Errorf("total = %d after %d computations, want 6 after 2", got, computed)
+	}
+}
+
+func TestConnect(t *testing.T) {
+	s := New(reduceTest, testState{})
+	renders := 0
+	count := Connect(s, func(state testState) int { return state.Count }, func() { renders++ })
+
+	s.Dispatch(rename{Name: "unrelated"})
+	if renders != 0 {
+		t.Errorf("re-rendered %d times for an unrelated change", renders)
+	}
+
+	s.Dispatch(increment{})
+	if renders != 1 || count.Value() != 1 {
+		t.Errorf("value = %d after %d renders, want 1 after 1", count.Value(), renders)
+	}
+
+	count.Dispatch(increment{})
+	if renders != 2 || s.State().Count != 2 {
+		t.Errorf("count = %d after %d renders, want 2 after 2", s.State().Count, renders)
+	}
+
+	count.Close()
+	s.Dispatch(increment{})
+	if renders != 2 || count.Value() != 2 {
+		t.Errorf("the closed connection followed the store: value %d, %d renders", count.Value(), renders)
+	}
+}
+
+func TestPersistAndRestore(t *testing.T) {
+	storage := NewMemoryStorage()
+	initial := Restore(storage, "state", testState{Name: "initial"})
+	if initial.Name != "initial" {
+		t.Fatalf("restored %+v from an empty storage, want the initial state", initial)
+	}
+
+	s := New(reduceTest, initial, Persist[testState](storage, "state"))
+	s.Dispatch(increment{})
+	s.Dispatch(rename{Name: "saved"})
+
+	saved, ok := storage.GetItem("state")
+	if !ok || saved != `{"Count":1,"Name":"saved"}` {
+		t.Fatalf("saved %q, want the state in JSON", saved)
+	}
+
+	// The fields missing from the saved state keep their initial value
+	restored := Restore(storage, "state", testState{Theme: "dark"})
+	if want := (testState{Count: 1, Name: "saved", Theme: "dark"}); restored != want {
+		t.Errorf("restored %+v, want %+v", restored, want)
+	}
+
+	storage.SetItem("state", "{broken")
+	if restored := Restore(storage, "state", testState{Theme: "dark"}); restored != (testState{Theme: "dark"}) {
+		t.Errorf("restored %+v from broken JSON, want the initial state", restored)
+	}
+}
+
+func TestDispatchFromListener(t *testing.T) {
+	s := New(reduceTest, testState{})
+	var seen []int
+	s.Subscribe(func(state testState) {
+		seen = append(seen, state.Count)
+		if state.Count == 1 {
+			s.Dispatch(increment{})
+		}
+	})
+	last := 0
+	s.Subscribe(func(state testState) { last = state.Count })
+
+	s.Dispatch(increment{})
+
+	if !reflect.DeepEqual(seen, []int{1, 2}) || last != 2 {
+		t.Errorf("seen %v, last %d, want [1 2] and 2", seen, last)
+	}
+}
+
+func TestConcurrentDispatchesEndWithTheLatestState(t *testing.T) {
+	const goroutines, dispatches = 8, 200
+	s := New(reduceTest, testState{})
+
+	var mutex sync.Mutex
+	delivered := []int{}
+	s.Subscribe(func(state testState) {
+		runtime.Gosched() // Lets the other dispatches run while notifying
+		mutex.Lock()
+		defer mutex.Unlock()
+		delivered = append(delivered, state.Count)
+	})
+	count := Connect(s, func(state testState) int { return state.Count }, nil)
+
+	var wg sync.WaitGroup
+	for range goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range dispatches {
+				s.Dispatch(increment{})
+			}
+		}()
+	}
+	wg.Wait()
+
+	const total = goroutines * dispatches
+	if s.State().Count != total {
+		t.Fatalf("count = %d, want %d", s.State().Count, total)
+	}
+	if count.Value() != total {
+		t.Errorf("connection value = %d, want %d", count.Value(), total)
+	}
+	for i := 1; i < len(delivered); i++ {
+		if delivered[i] <= delivered[i-1] {
+			t.Fatalf("state %d delivered after %d", delivered[i], delivered[i-1])
+		}
+	}
+	if delivered[len(delivered)-1] != total {
+		t.Errorf("last state delivered %d, want %d", delivered[len(delivered)-1], total)
+	}
+}
+
+// dispatchPanics dispatches the action and reports whether it panicked
+// It fails the test when the dispatch blocks, the store being locked
+func dispatchPanics(t *testing.T, s *Store[testState], action Action) bool {
+	t.Helper()
+	panicked := make(chan bool, 1)
+	go func() {
+		defer func() { panicked <- recover() != nil }()
+		s.Dispatch(action)
+	}()
+	select {
+	case result := <-panicked:
+		return result
+	case <-time.After(time.Second):
+		t.Fatalf("%v: the dispatch is blocked", action)
+		return false
+	}
+}
+
+func TestPanicsLeaveTheStoreUsable(t *testing.T) {
+	s := New(func(state testState, action Action) testState {
+		if action == "panic" {
+			panic("reducer")
+		}
+		return reduceTest(state, action)
+	}, testState{})
+	var seen []int
+	s.Subscribe(func(state testState) {
+		if state.Count == 1 {
+			panic("listener")
+		}
+		seen = append(seen, state.Count)
+	})
+
+	for _, action := range []Action{"panic", increment{}} {
+		if !dispatchPanics(t, s, action) {
+			t.Errorf("%v: the panic was not propagated", action)
+		}
+	}
+
+	// Neither locked nor still notifying: the next dispatch is delivered
+	if dispatchPanics(t, s, increment{}) {
+		t.Fatal("the dispatch panicked")
+	}
+	if !reflect.DeepEqual(seen, []int{2}) {
+		t.Errorf("listener saw %v, want [2]", seen)
+	}
+}
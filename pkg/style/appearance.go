@@ -1,5 +1,3 @@
-//go:build js && wasm
-
 // Package style provides type-safe CSS styling for Vortex components.
 // This file contains the functions to apply the background and appearance to a style.
 // It is used to apply the background and appearance to a style.
@@ -1,5 +1,3 @@
-//go:build js && wasm
-
 // common.go is a file that contains all the already generated code for the styling
 // it is used to avoid code duplication and to make the code more readable
 //
@@ -1,5 +1,3 @@
-//go:build js && wasm
-
 // Package style provides type-safe CSS styling for Vortex components.
 // This file contains the functions to generate the CSS content of a style
 // and the class name of a style.
@@ -28,26 +26,39 @@ package style
 import (
 	"fmt"
 	"hash/fnv"
+	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 )
 
 // GetClassName generate and return a class name unique and stable for a style
-// It uses an hash of the CSS content to ensure unicity
+// It uses an hash of the canonical CSS content to ensure unicity: two styles with the
+// same properties always get the same class name, from one run to the other
+// The name is "vtx-" followed by the 8 hexadecimal digits of the FNV-1a hash of the content
+//
+// The content is only generated again after an Update, the maps of the style edited directly
+// (its nested styles included) must be followed by s.Update() for the class name and CSS to change
 //
 // Should not be used directly, see style package documentation
 func (s *Style) GetClassName() string {
-	if s.className != "" {
+	if s.className != "" && !s.dirty {
 		return s.className // Return from the cache
 	}
+	s.dirty = false
 
 	// We generate first a CSS brut contents, WITHOUT the class name
 	rawCSSContent := s.genereateCSSContent()
+	if s.className != "" && rawCSSContent == s.content {
+		return s.className // Updated, but to the same content: the CSS is kept
+	}
 
 	// We hash this content to obtain a stable class name
 	h := fnv.New32a()
 	h.Write([]byte(rawCSSContent))
-	s.className = fmt.Sprintf("vtx-%d", h.Sum32())
+	s.content = rawCSSContent
+	s.className = fmt.Sprintf("vtx-%08x", h.Sum32())
+	s.css = ""
 
 	return s.className
 }
@@ -55,27 +66,30 @@ func (s *Style) GetClassName() string {
 // ToCSS convert a Style object in its textual CSS representation
 // It depends on the GetClassName method to generate the class name
 // BUT does not call it recursively
+// The rules are written one per line, in the canonical order (see rules)
 //
 // Should not be used directly, see style package documentation
 func (s *Style) ToCSS() string {
+	className := s.GetClassName() // We get the class name, clearing the cache if the style changed
 	if s.css != "" {
 		return s.css // Retrun from the cache
 	}
 
-	className := s.GetClassName() // We get the class name
 	var sb strings.Builder
 
-	// Generate the base styles
-	sb.WriteString(fmt.Sprintf(".%s {%s}\n", className, propsToCSS(s.Base)))
-
-	// Generate the pseudo-classes
-	for pseudo, props := range s.Pseudos {
-		sb.WriteString(fmt.Sprintf(".%s%s {%s}\n", className, pseudo, propsToCSS(props)))
+	// The keyframes come first, so the animations of the rules refer to known names
+	for _, keyframes := range s.keyframes() {
+		sb.WriteString(keyframes.ToCSS() + "\n")
 	}
 
-	// Generate the media queries
-	for query, props := range s.MediaQueries {
-		sb.WriteString(fmt.Sprintf("%s { .%s {%s} }\n", query, className, propsToCSS(props)))
+	for _, rule := range s.rules() {
+		selector := replaceNesting(rule.selector, "."+className)
+		css := fmt.Sprintf("%s {%s}", selector, propsToCSS(rule.props))
+		// Wrap the rule in its at-rules, the innermost last
+		for i := len(rule.atRules) - 1; i >= 0; i-- {
+			css = fmt.Sprintf("%s { %s }", rule.atRules[i], css)
+		}
+		sb.WriteString(css + "\n")
 	}
 
 	s.css = sb.String()
@@ -84,22 +98,170 @@ func (s *Style) ToCSS() string {
 
 // genereateCSSContent generate the CSS content of the style WITHOUT the class name
 // It is used only used to generate the class name
+// Every rule is delimited, so two different styles cannot produce the same content
 //
 // Should not be used directly, see style package documentation
 func (s *Style) genereateCSSContent() string {
 	var sb strings.Builder
+	for _, rule := range s.rules() {
+		sb.WriteString(strings.Join(rule.atRules, "") + "{" + rule.selector + "{" + propsToCSS(rule.props) + "}}")
+	}
+	return sb.String()
+}
+
+// cssRule is a rule of a style, relative to the class of the style
+type cssRule struct {
+	atRules  []string // Enclosing at-rules, outermost first, such as "@media (max-width: 768px)"
+	selector string   // Selector where "&" stands for the class, such as "&:hover"
+	props    Property // Declarations of the rule
+}
+
+// rules returns the rules of the style in a canonical order, independent of the maps
+//
+// Should not be used directly, see style package documentation
+func (s *Style) rules() []cssRule {
+	rules := []cssRule{{selector: "&", props: s.Base}}
+	return append(rules, s.collectRules("&", nil)...)
+}
 
-	// Generate the base styles
-	sb.WriteString(propsToCSS(s.Base))
+// collectRules returns the rules of the style under the selector and the at-rules, without its base rule:
+// the pseudo-classes (see pseudoOrder), the nested rules (by selector), then the queries (see compareMediaQueries)
+// The rules without declarations are skipped
+func (s *Style) collectRules(selector string, atRules []string) []cssRule {
+	rules := []cssRule{}
+	add := func(rule cssRule) {
+		if len(rule.props) > 0 {
+			rules = append(rules, rule)
+		}
+	}
 
-	for pseudo, props := range s.Pseudos {
-		sb.WriteString(pseudo + propsToCSS(props))
+	pseudos := make([]string, 0, len(s.Pseudos))
+	for pseudo := range s.Pseudos {
+		pseudos = append(pseudos, pseudo)
 	}
-	for query, props := range s.MediaQueries {
-		sb.WriteString(query + propsToCSS(props))
+	sort.Slice(pseudos, func(i, j int) bool {
+		return comparePseudos(pseudos[i], pseudos[j]) < 0
+	})
+	for _, pseudo := range pseudos {
+		add(cssRule{atRules: atRules, selector: appendToSelector(selector, pseudo), props: s.Pseudos[pseudo]})
+	}
+
+	nestedSelectors := make([]string, 0, len(s.Nested))
+	for nestedSelector := range s.Nested {
+		nestedSelectors = append(nestedSelectors, nestedSelector)
+	}
+	sort.Strings(nestedSelectors)
+	for _, nestedSelector := range nestedSelectors {
+		nested := s.Nested[nestedSelector]
+		fullSelector := nestSelector(selector, nestedSelector)
+		add(cssRule{atRules: atRules, selector: fullSelector, props: nested.Base})
+		rules = append(rules, nested.collectRules(fullSelector, atRules)...)
+	}
+
+	// The media queries and the full styles under an at-rule share the same order
+	queries := make([]string, 0, len(s.MediaQueries)+len(s.Queries))
+	for query := range s.MediaQueries {
+		queries = append(queries, query)
+	}
+	for query := range s.Queries {
+		if _, exists := s.MediaQueries[query]; !exists {
+			queries = append(queries, query)
+		}
+	}
+	sort.Slice(queries, func(i, j int) bool {
+		return compareMediaQueries(queries[i], queries[j]) < 0
+	})
+	for _, query := range queries {
+		queryAtRules := append(atRules[:len(atRules):len(atRules)], query)
+		add(cssRule{atRules: queryAtRules, selector: selector, props: s.MediaQueries[query]})
+		if queryStyle, exists := s.Queries[query]; exists {
+			add(cssRule{atRules: queryAtRules, selector: selector, props: queryStyle.Base})
+			rules = append(rules, queryStyle.collectRules(selector, queryAtRules)...)
+		}
+	}
+
+	return rules
+}
+
+// pseudoOrder is the order of the pseudo-classes in the generated CSS
+// It follows the usual cascade (LVHFA): a later state wins over an earlier one
+// The pseudo-classes not listed come after, in alphabetical order
+var pseudoOrder = []string{
+	":link",
+	":visited",
+	":hover",
+	":focus-within",
+	":focus",
+	":focus-visible",
+	":active",
+	":disabled",
+}
+
+// comparePseudos compares two pseudo-classes according to pseudoOrder
+func comparePseudos(a, b string) int {
+	rankA, rankB := rankOf(pseudoOrder, a), rankOf(pseudoOrder, b)
+	if rankA != rankB {
+		return rankA - rankB
+	}
+	return strings.Compare(a, b)
+}
+
+// rankOf returns the position of value in order, or len(order) when absent
+func rankOf(order []string, value string) int {
+	for i, v := range order {
+		if v == value {
+			return i
+		}
+	}
+	return len(order)
+}
+
+// mediaFeaturePattern matches the first "(min-feature: value)" or "(max-feature: value)" of a query
+var mediaFeaturePattern = regexp.MustCompile(`\((min|max)-([a-z-]+):\s*(-?[0-9.]+)([a-z%]*)\s*\)`)
+
+// compareMediaQueries compares two media queries so that the more specific one comes last
+// It is a total order, so the generated CSS does not depend on the order of the map:
+//   - the queries without min- or max- feature first, in alphabetical order
+//   - then by kind (max- before min-), feature and unit of their first min- or max- feature
+//   - for the same kind, feature and unit, the min- queries by ascending value (mobile first)
+//     and the max- queries by descending value (desktop first)
+//   - the rest in alphabetical order
+//
+// Values are only compared numerically for the same unit: 5em and 900px have no order of their own
+func compareMediaQueries(a, b string) int {
+	matchA := mediaFeaturePattern.FindStringSubmatch(a)
+	matchB := mediaFeaturePattern.FindStringSubmatch(b)
+	switch {
+	case matchA == nil && matchB == nil:
+		return strings.Compare(a, b)
+	case matchA == nil:
+		return -1
+	case matchB == nil:
+		return 1
+	}
+
+	// Kind, feature, then unit
+	for _, group := range []int{1, 2, 4} {
+		if c := strings.Compare(matchA[group], matchB[group]); c != 0 {
+			return c
+		}
 	}
-	return sb.String()
 
+	valueA, errA := strconv.ParseFloat(matchA[3], 64)
+	valueB, errB := strconv.ParseFloat(matchB[3], 64)
+	switch {
+	case errA != nil && errB == nil:
+		return -1
+	case errA == nil && errB != nil:
+		return 1
+	}
+	if errA == nil && valueA != valueB {
+		if (valueA < valueB) == (matchA[1] == "min") {
+			return -1
+		}
+		return 1
+	}
+	return strings.Compare(a, b)
 }
 
 // propsToCSS is a utilitary function to convert a propriety map to text
@@ -0,0 +1,135 @@
+package style
+
+import (
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// shuffledOptions returns options filling every map of a style: media queries, queries,
+// pseudo-classes and nested rules, in a random order
+func shuffledOptions(rng *rand.Rand) []StyleOption {
+	options := []StyleOption{
+		BackgroundColor(RGB(255, 255, 255)),
+		MediaQuery(MediaQueryTypeMinWidth, "900px", BackgroundColor(RGB(1, 0, 0))),
+		MediaQuery(MediaQueryTypeMinWidth, "1000px", BackgroundColor(RGB(2, 0, 0))),
+		MediaQuery(MediaQueryTypeMinWidth, "5em", BackgroundColor(RGB(3, 0, 0))),
+		MediaQuery(MediaQueryTypeMaxWidth, "600px", BackgroundColor(RGB(4, 0, 0))),
+		MediaQuery(MediaQueryTypeMaxWidth, "40rem", BackgroundColor(RGB(5, 0, 0))),
+		Media(MinWidth(Px(640)).And(PrefersReducedMotion()), BackgroundColor(RGB(6, 0, 0))),
+		Media(Print, BackgroundColor(RGB(7, 0, 0))),
+		Media(PrefersColorScheme(ColorSchemeDark), BackgroundColor(RGB(8, 0, 0))),
+		Media(MinWidth(Rem(48)), OnHover(BackgroundColor(RGB(9, 0, 0)))),
+		OnHover(BackgroundColor(RGB(10, 0, 0))),
+		OnFocus(BackgroundColor(RGB(11, 0, 0))),
+		Nested("& > p", BackgroundColor(RGB(12, 0, 0))),
+		Nested("& span", BackgroundColor(RGB(13, 0, 0))),
+	}
+	rng.Shuffle(len(options), func(i, j int) { options[i], options[j] = options[j], options[i] })
+	return options
+}
+
+func TestStyleOutputIsDeterministic(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	reference := New(shuffledOptions(rng)...)
+	wantCSS, wantClass := reference.ToCSS(), reference.GetClassName()
+
+	for i := 0; i < 200; i++ {
+		s := New(shuffledOptions(rng)...)
+		if css := s.ToCSS(); css != wantCSS {
+			t.Fatalf("run %d: the CSS differs\ngot:\n%s\nwant:\n%s", i, css, wantCSS)
+		}
+		if className := s.GetClassName(); className != wantClass {
+			t.Fatalf("run %d: got class %s, want %s", i, className, wantClass)
+		}
+	}
+}
+
+func TestCompareMediaQueriesIsATotalOrder(t *testing.T) {
+	queries := []string{
+		"(min-width: 900px)",
+		"(min-width: 1000px)",
+		"(min-width: 5em)",
+		"(min-width: 40em)",
+		"(max-width: 600px)",
+		"(max-width: 1200px)",
+		"(min-height: 300px)",
+		"screen and (min-width: 768px)",
+		"print",
+		"(prefers-color-scheme: dark)",
+		"(min-width: 1.2.3px)",
+	}
+
+	for _, a := range queries {
+		if c := compareMediaQueries(a, a); c != 0 {
+			t.Errorf("compare(%q, itself) = %d", a, c)
+		}
+		for _, b := range queries {
+			if sign(compareMediaQueries(a, b)) != -sign(compareMediaQueries(b, a)) {
+				t.Errorf("compare(%q, %q) is not antisymmetric", a, b)
+			}
+			for _, c := range queries {
+				if compareMediaQueries(a, b) < 0 && compareMediaQueries(b, c) < 0 && compareMediaQueries(a, c) >= 0 {
+					t.Errorf("%q < %q < %q but not %q < %q", a, b, c, a, c)
+				}
+			}
+		}
+	}
+
+	// Mobile first for the min- queries, desktop first for the max- queries
+	sorted := []string{"(min-width: 1000px)", "(max-width: 600px)", "(min-width: 900px)", "(max-width: 1200px)"}
+	sort.Slice(sorted, func(i, j int) bool { return compareMediaQueries(sorted[i], sorted[j]) < 0 })
+	want := []string{"(max-width: 1200px)", "(max-width: 600px)", "(min-width: 900px)", "(min-width: 1000px)"}
+	for i := range want {
+		if sorted[i] != want[i] {
+			t.Fatalf("got %v, want %v", sorted, want)
+		}
+	}
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
+
+func TestUpdateAfterEditingTheMapsChangesTheClassName(t *testing.T) {
+	s := New(BackgroundColor(RGB(255, 255, 255)))
+	before, beforeCSS := s.GetClassName(), s.ToCSS()
+
+	// The content is not generated again until the style is updated
+	s.Base["color"] = "red"
+	if s.GetClassName() != before {
+		t.Fatalf("class name changed without an Update")
+	}
+	s.Update()
+	after := s.GetClassName()
+	if after == before {
+		t.Fatalf("class name %s kept after editing Base", after)
+	}
+	if css := s.ToCSS(); css == beforeCSS || !strings.Contains(css, "color: red") {
+		t.Fatalf("stale CSS after editing Base: %q", css)
+	}
+
+	delete(s.Base, "color")
+	s.Update()
+	if s.GetClassName() != before || s.ToCSS() != beforeCSS {
+		t.Fatalf("got %s, want %s back once the edit is undone", s.GetClassName(), before)
+	}
+
+	s.Nested["& > h2"] = New(FontWeight(FontWeightBold))
+	s.Update()
+	if s.GetClassName() == before {
+		t.Fatal("class name kept after adding a nested rule")
+	}
+
+	s.Update(Opacity(0.5))
+	if !strings.Contains(s.ToCSS(), "opacity") {
+		t.Fatalf("the option is missing from the CSS: %q", s.ToCSS())
+	}
+}
@@ -0,0 +1,494 @@
+// Package style provides type-safe CSS styling for Vortex components.
+// This file contains the keyframe animations and the transitions of a style.
+// They run in the browser (on the compositor when possible), without a frame by frame
+// update in Go like the animation package.
+//
+// Basic Usage:
+//
+//   var pulse = style.NewKeyframes(
+//       style.From(style.Opacity(1)),
+//       style.At(50, style.Opacity(0.4)),
+//       style.To(style.Opacity(1)),
+//   )
+//
+//   style := style.New(
+//       style.Animation(pulse, 1200*time.Millisecond, style.TimingEaseInOut),
+//       style.AnimationIterationCount(style.IterationInfinite),
+//       style.Transition(style.NewTransition("opacity", 200*time.Millisecond, style.TimingEaseOut)),
+//   )
+//
+// The @keyframes rule is written with the CSS of every style using it, under a name hashed
+// from its steps: the same steps always get the same name.
+//
+// All properties available are (table with css equivalent)
+// | Property | CSS Equivalent |
+// | -------- | -------------- |
+// | Animation | animation-name, animation-duration, animation-timing-function |
+// | Animation Name | animation-name |
+// | Animation Duration | animation-duration |
+// | Animation Timing Function | animation-timing-function |
+// | Animation Delay | animation-delay |
+// | Animation Iteration Count | animation-iteration-count |
+// | Animation Direction | animation-direction |
+// | Animation Fill Mode | animation-fill-mode |
+// | Animation Play State | animation-play-state |
+// | Transition | transition |
+// | Transition Property | transition-property |
+// | Transition Duration | transition-duration |
+// | Transition Timing Function | transition-timing-function |
+// | Transition Delay | transition-delay |
+//
+// For more information, see the style package documentation
+
+package style
+
+import (
+	"fmt"
+	"hash/fnv"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// --- Keyframes
+
+// Keyframes is a @keyframes rule, the steps of a CSS animation
+// It is immutable once created, so its name is computed once
+type Keyframes struct {
+	steps []KeyframeStep
+	name  string
+	css   string
+}
+
+// KeyframeStep is a step of a keyframe animation, at an offset between 0 and 100 percent
+type KeyframeStep struct {
+	Offset float64
+	Props  Property
+}
+
+// At returns the step at the offset, in percent, applying the given styles
+// Only the base properties of the styles are kept
+func At(offset float64, properties ...StyleOption) KeyframeStep {
+	return KeyframeStep{Offset: offset, Props: New(properties...).Base}
+}
+
+// From returns the first step of the animation (0%)
+func From(properties ...StyleOption) KeyframeStep {
+	return At(0, properties...)
+}
+
+// To returns the last step of the animation (100%)
+func To(properties ...StyleOption) KeyframeStep {
+	return At(100, properties...)
+}
+
+// NewKeyframes creates the keyframes of the given steps
+// The steps are sorted by offset, the steps at the same offset are merged
+//
+// Usage examples :
+//
+//	var fadeIn = style.NewKeyframes(
+//	    style.From(style.Opacity(0)),
+//	    style.To(style.Opacity(1)),
+//	)
+func NewKeyframes(steps ...KeyframeStep) *Keyframes {
+	merged := make(map[float64]Property)
+	for _, step := range steps {
+		if step.Offset < 0 || step.Offset > 100 {
+			log.Printf("CSS validation warning: keyframe offset %v is out of [0, 100], it is clamped", step.Offset)
+			step.Offset = min(max(step.Offset, 0), 100)
+		}
+		if merged[step.Offset] == nil {
+			merged[step.Offset] = make(Property)
+		}
+		for key, value := range step.Props {
+			merged[step.Offset][key] = value
+		}
+	}
+
+	k := &Keyframes{}
+	for offset, props := range merged {
+		k.steps = append(k.steps, KeyframeStep{Offset: offset, Props: props})
+	}
+	sort.Slice(k.steps, func(i, j int) bool {
+		return k.steps[i].Offset < k.steps[j].Offset
+	})
+
+	// The name is the hash of the steps, like the class name of a style
+	var sb strings.Builder
+	for _, step := range k.steps {
+		sb.WriteString(fmt.Sprintf("%s {%s} ", formatOffset(step.Offset), propsToCSS(step.Props)))
+	}
+	content := strings.TrimSpace(sb.String())
+	h := fnv.New32a()
+	h.Write([]byte(content))
+	k.name = fmt.Sprintf("vtx-kf-%08x", h.Sum32())
+	k.css = fmt.Sprintf("@keyframes %s { %s }", k.name, content)
+	return k
+}
+
+// Name returns the stable name of the keyframes
+func (k *Keyframes) Name() string {
+	return k.name
+}
+
+// Steps returns the steps of the keyframes, sorted by offset
+func (k *Keyframes) Steps() []KeyframeStep {
+	return k.steps
+}
+
+// ToCSS returns the @keyframes rule
+func (k *Keyframes) ToCSS() string {
+	return k.css
+}
+
+func formatOffset(offset float64) string {
+	return strconv.FormatFloat(offset, 'f', -1, 64) + "%"
+}
+
+// keyframes returns the keyframes used by the style and its nested styles, sorted by name
+func (s *Style) keyframes() []*Keyframes {
+	found := make(map[string]*Keyframes)
+	var walk func(s *Style)
+	walk = func(s *Style) {
+		for name, k := range s.Keyframes {
+			found[name] = k
+		}
+		for _, nested := range s.Nested {
+			walk(nested)
+		}
+		for _, query := range s.Queries {
+			walk(query)
+		}
+	}
+	walk(s)
+
+	names := make([]string, 0, len(found))
+	for name := range found {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	keyframes := make([]*Keyframes, len(names))
+	for i, name := range names {
+		keyframes[i] = found[name]
+	}
+	return keyframes
+}
+
+// adoptKeyframes keeps the keyframes of a style merged into s, such as the style of a pseudo-class
+func (s *Style) adoptKeyframes(other *Style) {
+	for name, keyframes := range other.Keyframes {
+		s.Keyframes[name] = keyframes
+	}
+}
+
+// --- Timing
+
+// TimingFunction is a type that represents an easing function of an animation or a transition
+// Usage examples :
+//
+//	style.TimingEaseInOut
+//	style.CubicBezier(0.4, 0, 0.2, 1)
+//	style.Steps(8)
+type TimingFunction string
+
+const (
+	TimingEase      TimingFunction = "ease"
+	TimingEaseIn    TimingFunction = "ease-in"
+	TimingEaseOut   TimingFunction = "ease-out"
+	TimingEaseInOut TimingFunction = "ease-in-out"
+	TimingLinear    TimingFunction = "linear"
+	TimingStepStart TimingFunction = "step-start"
+	TimingStepEnd   TimingFunction = "step-end"
+)
+
+func (t TimingFunction) String() string {
+	return string(t)
+}
+
+// Validate checks the keyword, the cubic-bezier() or the steps() syntax of the timing function
+func (t TimingFunction) Validate() error {
+	value := strings.TrimSpace(string(t))
+	invalid := func(reason string) error {
+		return &ValidationError{Property: "timing function", Value: string(t), Reason: reason}
+	}
+
+	switch value {
+	case "ease", "ease-in", "ease-out", "ease-in-out", "linear", "step-start", "step-end",
+		"inherit", "initial", "revert", "unset":
+		return nil
+	}
+	if varPattern.MatchString(value) {
+		return nil
+	}
+
+	name, args, ok := strings.Cut(value, "(")
+	if !ok || !strings.HasSuffix(args, ")") {
+		return invalid("unknown keyword")
+	}
+	params := strings.Split(strings.TrimSuffix(args, ")"), ",")
+	for i := range params {
+		params[i] = strings.TrimSpace(params[i])
+	}
+
+	switch strings.TrimSpace(name) {
+	case "cubic-bezier":
+		if len(params) != 4 {
+			return invalid("cubic-bezier() takes 4 numbers")
+		}
+		for i, param := range params {
+			n, err := strconv.ParseFloat(param, 64)
+			if err != nil {
+				return invalid(fmt.Sprintf("%q is not a number", param))
+			}
+			// The x coordinates are times, within the animation
+			if i%2 == 0 && (n < 0 || n > 1) {
+				return invalid("the x coordinates of cubic-bezier() must be in [0, 1]")
+			}
+		}
+		return nil
+
+	case "steps":
+		if len(params) < 1 || len(params) > 2 {
+			return invalid("steps() takes a number of steps and an optional position")
+		}
+		n, err := strconv.Atoi(params[0])
+		if err != nil || n < 1 {
+			return invalid("the number of steps must be a positive integer")
+		}
+		if len(params) == 2 {
+			switch params[1] {
+			case "jump-start", "jump-end", "jump-both", "start", "end":
+			case "jump-none":
+				if n < 2 {
+					return invalid("steps() with jump-none takes at least 2 steps")
+				}
+			default:
+				return invalid(fmt.Sprintf("unknown step position %q", params[1]))
+			}
+		}
+		return nil
+	}
+	return invalid("unknown function")
+}
+
+// CubicBezier returns a custom easing curve
+func CubicBezier(x1, y1, x2, y2 float64) TimingFunction {
+	return TimingFunction(fmt.Sprintf("cubic-bezier(%g, %g, %g, %g)", x1, y1, x2, y2))
+}
+
+// Steps returns an easing jumping in n equal steps, such as the frames of a spinner
+func Steps(n int) TimingFunction {
+	return TimingFunction(fmt.Sprintf("steps(%d)", n))
+}
+
+// cssTime formats a duration as a CSS time
+func cssTime(d time.Duration) string {
+	return fmt.Sprintf("%dms", d.Milliseconds())
+}
+
+// --- Animation
+
+// Animation runs the keyframes on the element
+// Usage examples :
+//
+//	style.Animation(spin, time.Second, style.TimingLinear)
+func Animation(keyframes *Keyframes, duration time.Duration, timing TimingFunction) StyleOption {
+	validateCSSValue("animation-timing-function", timing)
+	return func(s *Style) {
+		AnimationName(keyframes)(s)
+		s.Base["animation-duration"] = cssTime(duration)
+		s.Base["animation-timing-function"] = timing.String()
+	}
+}
+
+// AnimationName sets the keyframes run on the element
+// The @keyframes rule is written with the CSS of the style
+func AnimationName(keyframes *Keyframes) StyleOption {
+	return func(s *Style) {
+		s.Keyframes[keyframes.Name()] = keyframes
+		s.Base["animation-name"] = keyframes.Name()
+	}
+}
+
+// AnimationDuration sets the duration of one cycle of the animation
+func AnimationDuration(duration time.Duration) StyleOption {
+	return func(s *Style) {
+		s.Base["animation-duration"] = cssTime(duration)
+	}
+}
+
+// AnimationTimingFunction sets the easing of the animation
+func AnimationTimingFunction(timing TimingFunction) StyleOption {
+	validateCSSValue("animation-timing-function", timing)
+	return func(s *Style) {
+		s.Base["animation-timing-function"] = timing.String()
+	}
+}
+
+// AnimationDelay sets the delay before the animation starts
+func AnimationDelay(delay time.Duration) StyleOption {
+	return func(s *Style) {
+		s.Base["animation-delay"] = cssTime(delay)
+	}
+}
+
+// IterationInfinite repeats the animation forever, see AnimationIterationCount
+const IterationInfinite = -1
+
+// AnimationIterationCount sets the number of cycles of the animation
+// Usage examples :
+//
+//	style.AnimationIterationCount(3)
+//	style.AnimationIterationCount(style.IterationInfinite)
+func AnimationIterationCount(count float64) StyleOption {
+	return func(s *Style) {
+		if count < 0 {
+			s.Base["animation-iteration-count"] = "infinite"
+			return
+		}
+		s.Base["animation-iteration-count"] = strconv.FormatFloat(count, 'f', -1, 64)
+	}
+}
+
+// AnimationDirectionValue is a type that represents the direction of an animation
+type AnimationDirectionValue string
+
+const (
+	AnimationDirectionNormal           AnimationDirectionValue = "normal"
+	AnimationDirectionReverse          AnimationDirectionValue = "reverse"
+	AnimationDirectionAlternate        AnimationDirectionValue = "alternate"
+	AnimationDirectionAlternateReverse AnimationDirectionValue = "alternate-reverse"
+)
+
+// AnimationDirection sets whether the cycles run forwards, backwards or alternate
+func AnimationDirection(value AnimationDirectionValue) StyleOption {
+	return func(s *Style) {
+		s.Base["animation-direction"] = string(value)
+	}
+}
+
+// AnimationFillModeValue is a type that represents the styles kept outside the run of an animation
+type AnimationFillModeValue string
+
+const (
+	AnimationFillModeNone      AnimationFillModeValue = "none"
+	AnimationFillModeForwards  AnimationFillModeValue = "forwards"
+	AnimationFillModeBackwards AnimationFillModeValue = "backwards"
+	AnimationFillModeBoth      AnimationFillModeValue = "both"
+)
+
+// AnimationFillMode sets the styles applied before and after the animation
+func AnimationFillMode(value AnimationFillModeValue) StyleOption {
+	return func(s *Style) {
+		s.Base["animation-fill-mode"] = string(value)
+	}
+}
+
+// AnimationPlayStateValue is a type that represents whether an animation runs
+type AnimationPlayStateValue string
+
+const (
+	AnimationPlayStateRunning AnimationPlayStateValue = "running"
+	AnimationPlayStatePaused  AnimationPlayStateValue = "paused"
+)
+
+// AnimationPlayState pauses or resumes the animation
+// Usage examples :
+//
+//	style.OnHover(style.AnimationPlayState(style.AnimationPlayStatePaused))
+func AnimationPlayState(value AnimationPlayStateValue) StyleOption {
+	return func(s *Style) {
+		s.Base["animation-play-state"] = string(value)
+	}
+}
+
+// --- Transition
+
+// TransitionValue is a type that represents the transition of a property
+// Usage examples :
+//
+//	style.NewTransition("opacity", 200*time.Millisecond, style.TimingEaseOut)
+//	style.NewTransition("transform", 300*time.Millisecond, style.TimingEase).WithDelay(100*time.Millisecond)
+type TransitionValue struct {
+	Property string
+	Duration time.Duration
+	Timing   TimingFunction
+	Delay    time.Duration
+}
+
+// NewTransition returns the transition of the property
+func NewTransition(property string, duration time.Duration, timing TimingFunction) TransitionValue {
+	return TransitionValue{Property: property, Duration: duration, Timing: timing}
+}
+
+// WithDelay returns the transition starting after the delay
+func (t TransitionValue) WithDelay(delay time.Duration) TransitionValue {
+	t.Delay = delay
+	return t
+}
+
+func (t TransitionValue) String() string {
+	value := fmt.Sprintf("%s %s %s", t.Property, cssTime(t.Duration), t.Timing)
+	if t.Delay > 0 {
+		value += " " + cssTime(t.Delay)
+	}
+	return value
+}
+
+func (t TransitionValue) Validate() error {
+	if t.Property == "" {
+		return &ValidationError{Property: "transition", Value: t.String(), Reason: "no property"}
+	}
+	if t.Duration < 0 {
+		return &ValidationError{Property: "transition", Value: t.String(), Reason: "negative duration"}
+	}
+	return t.Timing.Validate()
+}
+
+// Transition sets the transitions of the element, one per property
+// Usage examples :
+//
+//	style.Transition(
+//	    style.NewTransition("opacity", 200*time.Millisecond, style.TimingEaseOut),
+//	    style.NewTransition("transform", 300*time.Millisecond, style.TimingEase),
+//	)
+func Transition(values ...TransitionValue) StyleOption {
+	for _, value := range values {
+		validateCSSValue("transition", value)
+	}
+	return func(s *Style) {
+		s.Base["transition"] = strings.Join(CSSValuesToString(values...), ", ")
+	}
+}
+
+// TransitionProperty sets the properties animated by the transition
+func TransitionProperty(properties ...string) StyleOption {
+	return func(s *Style) {
+		s.Base["transition-property"] = strings.Join(properties, ", ")
+	}
+}
+
+// TransitionDuration sets the duration of the transition
+func TransitionDuration(duration time.Duration) StyleOption {
+	return func(s *Style) {
+		s.Base["transition-duration"] = cssTime(duration)
+	}
+}
+
+// TransitionTimingFunction sets the easing of the transition
+func TransitionTimingFunction(timing TimingFunction) StyleOption {
+	validateCSSValue("transition-timing-function", timing)
+	return func(s *Style) {
+		s.Base["transition-timing-function"] = timing.String()
+	}
+}
+
+// TransitionDelay sets the delay before the transition starts
+func TransitionDelay(delay time.Duration) StyleOption {
+	return func(s *Style) {
+		s.Base["transition-delay"] = cssTime(delay)
+	}
+}
@@ -0,0 +1,27 @@
+package style
+
+import "testing"
+
+func TestTimingFunctionValidate(t *testing.T) {
+	valid := []TimingFunction{
+		TimingEase, TimingEaseInOut, TimingLinear, TimingStepEnd,
+		CubicBezier(0.4, 0, 0.2, 1), CubicBezier(0.68, -0.55, 0.27, 1.55),
+		Steps(8), "steps(4, jump-none)", "steps(2, start)", "var(--vtx-easing)",
+	}
+	for _, timing := range valid {
+		if err := timing.Validate(); err != nil {
+			t.Errorf("%s: unexpected error %v", timing, err)
+		}
+	}
+
+	invalid := []TimingFunction{
+		"", "eas", "cubic-bezier(0.4, 0, 0.2)", CubicBezier(1.5, 0, 0.2, 1),
+		"cubic-bezier(a, 0, 0.2, 1)", Steps(0), "steps(1, jump-none)", "steps(3, middle)",
+		"steps(3", "bounce(1)",
+	}
+	for _, timing := range invalid {
+		if err := timing.Validate(); err == nil {
+			t.Errorf("%q: no error", timing)
+		}
+	}
+}
@@ -1,5 +1,3 @@
-//go:build js && wasm
-
 // Package style provides type-safe CSS styling for Vortex components.
 // This file contains the functions to apply the media queries to a style.
 // It is used to apply the media queries to a style.
@@ -32,11 +30,30 @@
 // | Media Query Type Min Device Resolution | @media (min-device-resolution: 600px)        |
 // | Media Query Type Max Device Resolution | @media (max-device-resolution: 600px)        |
 //
+// The queries can be composed (Media, Container, Supports) from typed conditions combined
+// with And, Or and Not, and they hold full styles: pseudo-classes and nested rules included.
+//
+//   style := style.New(
+//       style.Media(style.Screen.And(style.Orientation(style.OrientationLandscape)),
+//           style.OnHover(style.Opacity(0.8)),
+//       ),
+//       style.Media(style.PrefersReducedMotion(), style.Opacity(1)),
+//       style.Below(style.BreakpointMD, style.Padding(style.PaddingAll, style.Rem(1))),
+//       style.Supports(style.SupportsDeclaration("display", "grid"), style.Display(style.DisplayGrid)),
+//   )
+//
 // For more information, see the style package documentation
 
 package style
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"slices"
+	"strings"
+)
 
 // --- Media Queries
 
@@ -63,21 +80,436 @@ const (
 )
 
 // MediaQuery applies the given styles for the given media query
+// The styles can hold pseudo-classes and nested rules (see Media)
+// The declarations are written in the MediaQueries of the style, the other rules in its Queries
 func MediaQuery(queryType MediaQueryType, queryValue string, properties ...StyleOption) StyleOption {
+	// Syntaxe CSS correcte, par ex: "@media (min-width: 600px)"
+	atRule := "@media " + conditionFor("media", Feature(string(queryType), queryValue))
 	return func(s *Style) {
-		mediaStyle := New(properties...)
+		Query(atRule, properties...)(s)
+		query := s.Queries[atRule]
 
-		// Syntaxe CSS correcte, par ex: "@media (min-width: 600px)"
-		fullQuery := fmt.Sprintf("@media (%s: %s)", queryType, queryValue)
+		if s.MediaQueries[atRule] == nil {
+			s.MediaQueries[atRule] = make(Property)
+		}
+		maps.Copy(s.MediaQueries[atRule], query.Base)
+		clear(query.Base)
 
-		// On initialise la map si elle est nil, une bonne pratique
-		if s.MediaQueries[fullQuery] == nil {
-			s.MediaQueries[fullQuery] = make(Property)
+		if len(query.Pseudos) == 0 && len(query.MediaQueries) == 0 && len(query.Nested) == 0 &&
+			len(query.Queries) == 0 && len(query.Keyframes) == 0 {
+			delete(s.Queries, atRule)
 		}
+	}
+}
+
+// --- Composable queries
+
+// Condition is the condition of a media, container or feature query
+// Conditions are combined with And, Or and Not
+//
+// A media type (Screen, Print, AllMedia) always starts its query, and the alternatives
+// holding a media type are written as a query list, separated by commas.
+// A combination CSS cannot express, such as the negation of a media type and another
+// condition, is invalid: Validate returns the error and the query matches nothing.
+//
+// Usage examples :
+//
+//	style.Screen.And(style.MinWidth(style.Px(768)))                 // screen and (min-width: 768.00px)
+//	style.MinWidth(style.Px(768)).And(style.Screen)                 // screen and (min-width: 768.00px)
+//	style.PrefersColorScheme(style.ColorSchemeDark).Or(style.Print) // (prefers-color-scheme: dark), print
+//	style.Not(style.SupportsDeclaration("display", "grid"))         // not (display: grid)
+type Condition struct {
+	queries []mediaQuery // Alternatives, written as a query list
+	err     error
+}
+
+// mediaQuery is a single query of a condition: an optional media type and a condition without type
+type mediaQuery struct {
+	not       bool   // The whole query is negated, only with a media type
+	mediaType string // "screen", "print", "all", or empty
+	expr      string // Condition on the features, or empty
+}
+
+// noMedia is the query matching no media at all
+var noMedia = mediaQuery{not: true, mediaType: "all"}
+
+func (c Condition) String() string {
+	if c.err != nil {
+		return noMedia.String()
+	}
+	parts := make([]string, len(c.queries))
+	for i, q := range c.queries {
+		parts[i] = q.String()
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Validate returns the error of a combination CSS cannot express
+func (c Condition) Validate() error {
+	return c.err
+}
+
+func (q mediaQuery) String() string {
+	if q.mediaType == "" {
+		return q.expr
+	}
+	query := q.mediaType
+	if q.not {
+		query = "not " + query
+	}
+	if q.expr != "" {
+		// Only "and" can follow a media type
+		query += " and " + wrapCondition(q.expr, " and ")
+	}
+	return query
+}
 
-		// On fusionne les propriétés
-		for key, value := range mediaStyle.Base {
-			s.MediaQueries[fullQuery][key] = value
+// And returns the condition met when c and all the others are met
+func (c Condition) And(others ...Condition) Condition {
+	result := c
+	for _, o := range others {
+		result = result.and(o)
+	}
+	return result
+}
+
+// and distributes the conjunction over the alternatives of both conditions
+func (c Condition) and(o Condition) Condition {
+	if err := errors.Join(c.err, o.err); err != nil {
+		return Condition{err: err}
+	}
+	if len(c.queries) == 0 {
+		return o
+	}
+	if len(o.queries) == 0 {
+		return c
+	}
+
+	result := Condition{}
+	for _, a := range c.queries {
+		for _, b := range o.queries {
+			q, matches, err := andQueries(a, b)
+			if err != nil {
+				return Condition{err: err}
+			}
+			if matches {
+				result.queries = append(result.queries, q)
+			}
 		}
 	}
+	if len(result.queries) == 0 {
+		result.queries = []mediaQuery{noMedia} // Two different media types: nothing matches
+	}
+	return result
+}
+
+// andQueries returns the query met when both are, and false when no media can meet it
+func andQueries(a, b mediaQuery) (mediaQuery, bool, error) {
+	if (a.not && !b.isAll()) || (b.not && !a.isAll()) {
+		return mediaQuery{}, false, &ValidationError{
+			Property: "media",
+			Value:    a.String() + " and " + b.String(),
+			Reason:   "a negated media type cannot be combined with another condition",
+		}
+	}
+	if a.not {
+		return a, true, nil
+	}
+	if b.not {
+		return b, true, nil
+	}
+
+	mediaType := a.mediaType
+	switch {
+	case b.mediaType == "" || b.mediaType == a.mediaType:
+	case a.mediaType == "" || a.mediaType == "all":
+		mediaType = b.mediaType
+	case b.mediaType != "all":
+		return mediaQuery{}, false, nil
+	}
+
+	expr := a.expr
+	switch {
+	case a.expr == "":
+		expr = b.expr
+	case b.expr != "":
+		expr = wrapCondition(a.expr, " and ") + " and " + wrapCondition(b.expr, " and ")
+	}
+	return mediaQuery{mediaType: mediaType, expr: expr}, true, nil
+}
+
+// isAll reports whether the query is met by every media, without condition
+func (q mediaQuery) isAll() bool {
+	return !q.not && (q.mediaType == "" || q.mediaType == "all") && q.expr == ""
+}
+
+// Or returns the condition met when c or one of the others is met
+func (c Condition) Or(others ...Condition) Condition {
+	result := c
+	for _, o := range others {
+		result = result.or(o)
+	}
+	return result
+}
+
+// or joins the conditions with "or" when they have no media type, as a query list otherwise
+func (c Condition) or(o Condition) Condition {
+	if err := errors.Join(c.err, o.err); err != nil {
+		return Condition{err: err}
+	}
+	if len(c.queries) == 0 {
+		return o
+	}
+	if len(o.queries) == 0 {
+		return c
+	}
+
+	if len(c.queries) == 1 && len(o.queries) == 1 && c.queries[0].mediaType == "" && o.queries[0].mediaType == "" {
+		expr := wrapCondition(c.queries[0].expr, " or ") + " or " + wrapCondition(o.queries[0].expr, " or ")
+		return Condition{queries: []mediaQuery{{expr: expr}}}
+	}
+	return Condition{queries: append(slices.Clone(c.queries), o.queries...)}
+}
+
+// Not returns the condition met when c is not
+// The alternatives of a query list are negated one by one and joined with And
+func Not(c Condition) Condition {
+	if c.err != nil || len(c.queries) == 0 {
+		return c
+	}
+	if len(c.queries) > 1 {
+		result := Not(Condition{queries: c.queries[:1]})
+		for _, q := range c.queries[1:] {
+			result = result.And(Not(Condition{queries: []mediaQuery{q}}))
+		}
+		return result
+	}
+
+	q := c.queries[0]
+	if q.mediaType != "" {
+		q.not = !q.not
+		return Condition{queries: []mediaQuery{q}}
+	}
+	return Condition{queries: []mediaQuery{{expr: "not " + wrapCondition(q.expr, "")}}}
+}
+
+// wrapCondition wraps the expression in parentheses before joining it with the operator
+// CSS does not mix "and" and "or", and a negation is only combined in parentheses
+// An empty operator wraps any combination, for a negation
+func wrapCondition(expr, operator string) string {
+	for _, other := range []string{" and ", " or "} {
+		if other != operator && strings.Contains(expr, other) {
+			return "(" + expr + ")"
+		}
+	}
+	if strings.HasPrefix(expr, "not ") {
+		return "(" + expr + ")"
+	}
+	return expr
+}
+
+// Feature returns the condition on a media or container feature: (name: value)
+// An empty name or value, or a value which would end the rule, makes the condition invalid
+func Feature(name, value string) Condition {
+	feature := fmt.Sprintf("(%s: %s)", name, value)
+	if !isFeatureName(name) || strings.TrimSpace(value) == "" || strings.ContainsAny(value, "{};") {
+		return Condition{err: &ValidationError{Property: "feature", Value: feature, Reason: "a feature needs a name and a value"}}
+	}
+	return Condition{queries: []mediaQuery{{expr: feature}}}
+}
+
+// isFeatureName reports whether the name is a CSS identifier, such as "min-width"
+func isFeatureName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, c := range name {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+// Media types
+var (
+	Screen   = Condition{queries: []mediaQuery{{mediaType: "screen"}}}
+	Print    = Condition{queries: []mediaQuery{{mediaType: "print"}}}
+	AllMedia = Condition{queries: []mediaQuery{{mediaType: "all"}}}
+)
+
+// Size features, for media and container queries
+func MinWidth(value LengthValue) Condition  { return Feature("min-width", value.String()) }
+func MaxWidth(value LengthValue) Condition  { return Feature("max-width", value.String()) }
+func MinHeight(value LengthValue) Condition { return Feature("min-height", value.String()) }
+func MaxHeight(value LengthValue) Condition { return Feature("max-height", value.String()) }
+
+// OrientationValue is the orientation of the viewport
+type OrientationValue string
+
+const (
+	OrientationPortrait  OrientationValue = "portrait"
+	OrientationLandscape OrientationValue = "landscape"
+)
+
+// Orientation returns the condition on the orientation of the viewport
+func Orientation(value OrientationValue) Condition {
+	return Feature("orientation", string(value))
+}
+
+// ColorSchemeValue is the color scheme preferred by the user
+type ColorSchemeValue string
+
+const (
+	ColorSchemeLight ColorSchemeValue = "light"
+	ColorSchemeDark  ColorSchemeValue = "dark"
+)
+
+// PrefersColorScheme returns the condition on the color scheme preferred by the user
+func PrefersColorScheme(value ColorSchemeValue) Condition {
+	return Feature("prefers-color-scheme", string(value))
+}
+
+// PrefersReducedMotion returns the condition met when the user asks for less motion
+func PrefersReducedMotion() Condition {
+	return Feature("prefers-reduced-motion", "reduce")
+}
+
+// SupportsDeclaration returns the feature condition met when the browser supports the declaration
+func SupportsDeclaration(property, value string) Condition {
+	return Feature(property, value)
+}
+
+// Media applies the given styles when the media condition is met
+// The styles are full styles: pseudo-classes and nested rules are scoped in the query too
+//
+// Usage examples :
+//
+//	style.Media(style.PrefersColorScheme(style.ColorSchemeDark),
+//	    style.BackgroundColor(style.ColorBlack),
+//	    style.OnHover(style.BackgroundColor(style.ColorGray)),
+//	)
+func Media(condition Condition, properties ...StyleOption) StyleOption {
+	return Query("@media "+conditionFor("media", condition), properties...)
+}
+
+// Container applies the given styles when the nearest container meets the condition
+// The container is declared on an ancestor with ContainerType
+func Container(condition Condition, properties ...StyleOption) StyleOption {
+	return Query("@container "+conditionFor("container", condition), properties...)
+}
+
+// NamedContainer applies the given styles when the named container meets the condition
+func NamedContainer(name string, condition Condition, properties ...StyleOption) StyleOption {
+	return Query("@container "+name+" "+conditionFor("container", condition), properties...)
+}
+
+// Supports applies the given styles when the browser supports the feature condition
+func Supports(condition Condition, properties ...StyleOption) StyleOption {
+	return Query("@supports "+conditionFor("supports", condition), properties...)
+}
+
+// conditionFor returns the condition written after the at-rule, warning when it is invalid
+// An invalid condition is written as "not all": the rule is dropped and the styles never apply
+func conditionFor(atRule string, condition Condition) string {
+	if err := validateCondition(atRule, condition); err != nil {
+		log.Printf("CSS validation warning: %v", err)
+		return noMedia.String()
+	}
+	return condition.String()
+}
+
+// validateCondition returns the error of a condition the at-rule cannot hold
+// Only the media queries take media types and query lists
+func validateCondition(atRule string, condition Condition) error {
+	if err := condition.Validate(); err != nil {
+		return err
+	}
+	if len(condition.queries) == 0 {
+		return &ValidationError{Property: atRule, Value: "", Reason: "the condition is empty"}
+	}
+	if atRule == "media" {
+		return nil
+	}
+	if len(condition.queries) > 1 || condition.queries[0].mediaType != "" {
+		return &ValidationError{Property: atRule, Value: condition.String(), Reason: "only a media query takes media types"}
+	}
+	return nil
+}
+
+// Query applies the given styles under any at-rule, such as "@media print"
+// Several calls with the same at-rule are merged
+func Query(atRule string, properties ...StyleOption) StyleOption {
+	return func(s *Style) {
+		if query, exists := s.Queries[atRule]; exists {
+			query.Update(properties...)
+			return
+		}
+		s.Queries[atRule] = New(properties...)
+	}
+}
+
+// ContainerTypeValue is the type of containment of a query container
+type ContainerTypeValue string
+
+const (
+	ContainerTypeInlineSize ContainerTypeValue = "inline-size"
+	ContainerTypeSize       ContainerTypeValue = "size"
+	ContainerTypeNormal     ContainerTypeValue = "normal"
+)
+
+// ContainerType makes the element a query container for its descendants
+func ContainerType(value ContainerTypeValue) StyleOption {
+	return func(s *Style) {
+		s.Base["container-type"] = string(value)
+	}
+}
+
+// ContainerName names the query container, see NamedContainer
+func ContainerName(name string) StyleOption {
+	return func(s *Style) {
+		s.Base["container-name"] = name
+	}
+}
+
+// --- Breakpoints
+
+// Breakpoint is a named viewport width of the responsive layout
+type Breakpoint struct {
+	Name  string
+	Width LengthValue
+}
+
+// Breakpoint presets
+var (
+	BreakpointSM  = Breakpoint{Name: "sm", Width: Px(640)}
+	BreakpointMD  = Breakpoint{Name: "md", Width: Px(768)}
+	BreakpointLG  = Breakpoint{Name: "lg", Width: Px(1024)}
+	BreakpointXL  = Breakpoint{Name: "xl", Width: Px(1280)}
+	Breakpoint2XL = Breakpoint{Name: "2xl", Width: Px(1536)}
+)
+
+// Up returns the condition met from the breakpoint and above
+func (b Breakpoint) Up() Condition {
+	return MinWidth(b.Width)
+}
+
+// Down returns the condition met below the breakpoint
+// The width stops just before the breakpoint, so Up and Down never overlap
+func (b Breakpoint) Down() Condition {
+	return MaxWidth(LengthValue{Value: b.Width.Value - 0.02, Unit: b.Width.Unit})
+}
+
+// Above applies the given styles from the breakpoint and above (mobile first)
+//
+// Usage examples :
+//
+//	style.Above(style.BreakpointMD, style.FlexDirection(style.FlexDirectionRow))
+func Above(b Breakpoint, properties ...StyleOption) StyleOption {
+	return Media(b.Up(), properties...)
+}
+
+// Below applies the given styles below the breakpoint (desktop first)
+func Below(b Breakpoint, properties ...StyleOption) StyleOption {
+	return Media(b.Down(), properties...)
 }
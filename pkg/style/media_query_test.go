@@ -0,0 +1,130 @@
+package style
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConditionString(t *testing.T) {
+	dark := PrefersColorScheme(ColorSchemeDark)
+	wide := MinWidth(Px(768))
+	landscape := Orientation(OrientationLandscape)
+
+	tests := []struct {
+		name      string
+		condition Condition
+		want      string
+	}{
+		{"type and feature", Screen.And(wide), "screen and (min-width: 768.00px)"},
+		{"feature and type", wide.And(Screen), "screen and (min-width: 768.00px)"},
+		{"features", wide.And(landscape, dark), "(min-width: 768.00px) and (orientation: landscape) and (prefers-color-scheme: dark)"},
+		{"feature or type", dark.Or(Print), "(prefers-color-scheme: dark), print"},
+		{"features or", wide.Or(dark), "(min-width: 768.00px) or (prefers-color-scheme: dark)"},
+		{"or under type", Screen.And(wide.Or(dark)), "screen and ((min-width: 768.00px) or (prefers-color-scheme: dark))"},
+		{"and over a list", dark.Or(Print).And(wide), "(prefers-color-scheme: dark) and (min-width: 768.00px), print and (min-width: 768.00px)"},
+		{"and under or", wide.And(dark).Or(landscape), "((min-width: 768.00px) and (prefers-color-scheme: dark)) or (orientation: landscape)"},
+		{"all and type", AllMedia.And(Print), "print"},
+		{"different types", Screen.And(Print), "not all"},
+		{"not feature", Not(wide), "not (min-width: 768.00px)"},
+		{"not and", Not(wide.And(dark)), "not ((min-width: 768.00px) and (prefers-color-scheme: dark))"},
+		{"not and feature", Not(wide).And(dark), "(not (min-width: 768.00px)) and (prefers-color-scheme: dark)"},
+		{"not type", Not(Screen.And(wide)), "not screen and (min-width: 768.00px)"},
+		{"not not type", Not(Not(Print)), "print"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.condition.Validate(); err != nil {
+				t.Fatalf("Validate() = %v", err)
+			}
+			if got := tt.condition.String(); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConditionRejectsNegatedTypeCombinations(t *testing.T) {
+	condition := Not(Print).And(MinWidth(Px(768)))
+	if condition.Validate() == nil {
+		t.Fatal("Validate() = nil for a negated media type and a feature")
+	}
+	if got := condition.String(); got != "not all" {
+		t.Errorf("got %q, want %q", got, "not all")
+	}
+}
+
+func TestMediaQueryFillsMediaQueries(t *testing.T) {
+	s := New(
+		MediaQuery(MediaQueryTypeMaxWidth, "600px", Opacity(0.5), OnHover(Opacity(0.8))),
+		MediaQuery(MediaQueryTypeMaxWidth, "600px", Display(DisplayBlock)),
+	)
+	const atRule = "@media (max-width: 600px)"
+
+	if props := s.MediaQueries[atRule]; props["opacity"] == "" || props["display"] == "" {
+		t.Fatalf("MediaQueries[%q] = %v, want the opacity and the display", atRule, props)
+	}
+	if len(s.List()) != 2 {
+		t.Errorf("List() has %d options, want 2", len(s.List()))
+	}
+	query, exists := s.Queries[atRule]
+	if !exists || len(query.Base) != 0 || len(query.Pseudos) != 1 {
+		t.Fatalf("Queries[%q] = %+v, want the hover rule alone", atRule, query)
+	}
+	if css := s.ToCSS(); !strings.Contains(css, ":hover") {
+		t.Errorf("CSS lost the hover rule:\n%s", css)
+	}
+}
+
+func TestAtRuleConditions(t *testing.T) {
+	wide := MinWidth(Px(400))
+	tests := []struct {
+		name   string
+		option StyleOption
+		atRule string
+	}{
+		{"media", Media(Screen.And(wide)), "@media screen and (min-width: 400.00px)"},
+		{"media list", Media(Print.Or(wide)), "@media print, (min-width: 400.00px)"},
+		{"media empty", Media(Condition{}), "@media not all"},
+		{"media malformed", Media(Feature("", "1px")), "@media not all"},
+		{"media query without value", MediaQuery(MediaQueryTypeMaxWidth, ""), "@media not all"},
+		{"container", Container(wide.Or(Orientation(OrientationPortrait))), "@container (min-width: 400.00px) or (orientation: portrait)"},
+		{"container empty", Container(Condition{}), "@container not all"},
+		{"container media type", Container(Screen.And(wide)), "@container not all"},
+		{"container list", Container(wide.Or(Print)), "@container not all"},
+		{"named container", NamedContainer("card", wide), "@container card (min-width: 400.00px)"},
+		{"supports", Supports(Not(SupportsDeclaration("display", "grid"))), "@supports not (display: grid)"},
+		{"supports empty", Supports(Condition{}), "@supports not all"},
+		{"supports media type", Supports(Print), "@supports not all"},
+		{"supports malformed", Supports(SupportsDeclaration("display", "")), "@supports not all"},
+		{"supports injected", Supports(SupportsDeclaration("color", "red} body {color: red")), "@supports not all"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(Opacity(0.5), tt.option)
+			if _, exists := s.Queries[tt.atRule]; !exists {
+				if _, exists := s.MediaQueries[tt.atRule]; !exists {
+					t.Fatalf("no rule under %q in %v", tt.atRule, s.ToCSS())
+				}
+			}
+		})
+	}
+
+	for _, tt := range []struct {
+		atRule    string
+		condition Condition
+		valid     bool
+	}{
+		{"media", Print.Or(wide), true},
+		{"container", wide, true},
+		{"supports", SupportsDeclaration("display", "grid"), true},
+		{"media", Condition{}, false},
+		{"container", Screen, false},
+		{"container", wide.Or(Print), false},
+		{"supports", Feature("display grid", "x"), false},
+		{"supports", Feature("gap", " "), false},
+	} {
+		if err := validateCondition(tt.atRule, tt.condition); (err == nil) != tt.valid {
+			t.Errorf("validateCondition(%s, %q) = %v, want valid %v", tt.atRule, tt.condition, err, tt.valid)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+// Package style provides type-safe CSS styling for Vortex components.
+// This file contains the functions to apply nested rules to a style.
+// It is used to style the children of an element, its pseudo-elements or its attribute states.
+//
+// A nested rule is a full style whose selector is written relative to the class of the
+// parent style, "&" standing for it. The nested rules are scoped under the generated class
+// and are part of its hash. They can be nested themselves.
+//
+// Basic Usage:
+//
+//   card := style.New(
+//       style.Child("h2", style.FontSize(style.Rem(1.5))),             // .vtx-x > h2
+//       style.Descendant("li",
+//           style.NthChild("2n", style.BackgroundColor(style.ColorLightGray)), // .vtx-x li:nth-child(2n)
+//       ),
+//       style.PseudoElement("before", style.Content("→")),               // .vtx-x::before
+//       style.Attr("aria-expanded", "true", style.FontWeight(style.FontWeightBold)), // .vtx-x[aria-expanded="true"]
+//   )
+//
+// For more information, see the style package documentation
+
+package style
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Nested applies the given styles to the elements matching the selector
+// The selector is relative to the class of the style, written "&": "& > h2", "&.active", "& + &"
+// A selector without "&" applies to the descendants: "li" is "& li"
+// A selector starting with a pseudo-class or a pseudo-element applies to the element: "::after" is "&::after"
+// Each selector of a list is scoped: "h2, h3" is "& h2, & h3"
+// Several calls with the same selector are merged
+func Nested(selector string, properties ...StyleOption) StyleOption {
+	selector = scopeSelector(selector)
+	return func(s *Style) {
+		if nested, exists := s.Nested[selector]; exists {
+			nested.Update(properties...)
+			return
+		}
+		s.Nested[selector] = New(properties...)
+	}
+}
+
+// Child applies the given styles to the direct children matching the selector (& > selector)
+func Child(selector string, properties ...StyleOption) StyleOption {
+	return Nested("& > "+selector, properties...)
+}
+
+// Descendant applies the given styles to the descendants matching the selector (& selector)
+func Descendant(selector string, properties ...StyleOption) StyleOption {
+	return Nested("& "+selector, properties...)
+}
+
+// Attr applies the given styles when the element has the attribute with the value (&[name="value"])
+// An empty value only requires the attribute: &[name]
+func Attr(name, value string, properties ...StyleOption) StyleOption {
+	if value == "" {
+		return Nested("&["+name+"]", properties...)
+	}
+	return Nested("&["+name+"="+cssString(value)+"]", properties...)
+}
+
+// PseudoElement applies the given styles to a pseudo-element of the element (&::name)
+// Ex: "before", "after", "placeholder", "selection", "marker"
+func PseudoElement(name string, properties ...StyleOption) StyleOption {
+	return Nested("&::"+strings.TrimLeft(name, ":"), properties...)
+}
+
+// NthChild applies the given styles when the element matches :nth-child(formula)
+// Ex: "2n", "odd", "3n+1"
+func NthChild(formula string, properties ...StyleOption) StyleOption {
+	return Nested("&:nth-child("+formula+")", properties...)
+}
+
+// Content sets the content of a pseudo-element, as a quoted string
+func Content(value string) StyleOption {
+	return func(s *Style) {
+		s.Base["content"] = cssString(value)
+	}
+}
+
+// scopeSelector writes each selector of the list relative to "&" (see Nested)
+func scopeSelector(selector string) string {
+	parts := splitSelectorList(selector)
+	for i, part := range parts {
+		switch {
+		case containsNesting(part):
+		case strings.HasPrefix(part, ":"):
+			parts[i] = "&" + part
+		default:
+			parts[i] = "& " + part
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// nestSelector replaces "&" in the nested selector list by the parent selector list
+// Every selector of the nested list is written for every selector of the parent list:
+// "& span" under "&.a, &.b" is "&.a span, &.b span"
+func nestSelector(parent, nested string) string {
+	var parts []string
+	for _, parentPart := range splitSelectorList(parent) {
+		for _, nestedPart := range splitSelectorList(nested) {
+			parts = append(parts, replaceNesting(nestedPart, parentPart))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// appendToSelector appends the suffix, such as a pseudo-class, to every selector of the list
+func appendToSelector(selector, suffix string) string {
+	parts := splitSelectorList(selector)
+	for i, part := range parts {
+		parts[i] = part + suffix
+	}
+	return strings.Join(parts, ", ")
+}
+
+// splitSelectorList splits a selector list on its top-level commas
+// The commas in parentheses (":is(h2, h3)"), attribute selectors and strings are kept
+func splitSelectorList(selector string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i := 0; i < len(selector); i++ {
+		switch selector[i] {
+		case '\\':
+			i++ // Escaped character
+		case '"', '\'':
+			i = skipQuoted(selector, i)
+		case '(', '[':
+			depth++
+		case ')', ']':
+			if depth > 0 {
+				depth--
+			}
+		case ',':
+			if depth == 0 {
+				parts = append(parts, selector[start:i])
+				start = i + 1
+			}
+		}
+	}
+	parts = append(parts, selector[start:])
+
+	selectors := parts[:0]
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			selectors = append(selectors, part)
+		}
+	}
+	return selectors
+}
+
+// skipQuoted returns the index of the quote closing the string opened at start
+func skipQuoted(selector string, start int) int {
+	for i := start + 1; i < len(selector); i++ {
+		switch selector[i] {
+		case '\\':
+			i++
+		case selector[start]:
+			return i
+		}
+	}
+	return len(selector)
+}
+
+// containsNesting reports whether the selector holds "&", outside of its strings
+func containsNesting(selector string) bool {
+	return replaceNesting(selector, "") != selector
+}
+
+// replaceNesting replaces "&" by the replacement in the selector, outside of its strings and escapes
+func replaceNesting(selector, replacement string) string {
+	var sb strings.Builder
+	for i := 0; i < len(selector); i++ {
+		switch selector[i] {
+		case '&':
+			sb.WriteString(replacement)
+		case '\\':
+			end := min(i+2, len(selector))
+			sb.WriteString(selector[i:end])
+			i = end - 1
+		case '"', '\'':
+			end := min(skipQuoted(selector, i)+1, len(selector))
+			sb.WriteString(selector[i:end])
+			i = end - 1
+		default:
+			sb.WriteByte(selector[i])
+		}
+	}
+	return sb.String()
+}
+
+// cssString writes the value as a double-quoted CSS string
+// The quotes and backslashes are escaped, the control characters are written as hexadecimal escapes
+// followed by a space ("\A " for a line feed), a NUL byte is replaced by U+FFFD as CSS requires
+func cssString(value string) string {
+	var sb strings.Builder
+	sb.WriteByte('"')
+	for _, r := range value {
+		switch {
+		case r == 0:
+			sb.WriteRune('\uFFFD')
+		case r == '"' || r == '\\':
+			sb.WriteByte('\\')
+			sb.WriteRune(r)
+		case r < 0x20 || r == 0x7F:
+			fmt.Fprintf(&sb, "\\%X ", r)
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	sb.WriteByte('"')
+	return sb.String()
+}
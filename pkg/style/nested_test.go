@@ -0,0 +1,78 @@
+package style
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNestedSelectors(t *testing.T) {
+	tests := []struct {
+		name   string
+		option StyleOption
+		want   string
+	}{
+		{"descendant", Nested("li", Opacity(0.5)), "& li"},
+		{"list", Nested("h2, h3", Opacity(0.5)), "& h2, & h3"},
+		{"list with functions", Nested(":is(h2, h3) > a, p", Opacity(0.5)), "&:is(h2, h3) > a, & p"},
+		{"pseudo-element", Nested("::after", Opacity(0.5)), "&::after"},
+		{"pseudo-class", Nested(":first-child", Opacity(0.5)), "&:first-child"},
+		{"explicit", Nested("& + &", Opacity(0.5)), "& + &"},
+		{"attribute with comma", Nested(`[title="a, b"]`, Opacity(0.5)), `& [title="a, b"]`},
+		{"attribute value", Attr("title", "say \"hi\"\n&", Opacity(0.5)), `&[title="say \"hi\"\A &"]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(tt.option)
+			if _, exists := s.Nested[tt.want]; !exists || len(s.Nested) != 1 {
+				t.Fatalf("nested selectors %v, want [%s]", keys(s.Nested), tt.want)
+			}
+		})
+	}
+}
+
+func TestNestedSelectorListsAreCombined(t *testing.T) {
+	s := New(
+		Nested("h2, h3",
+			Nested("a, span", Opacity(0.5)),
+			OnHover(Opacity(0.8)),
+		),
+		Attr("data-label", "a&b", Opacity(0.2)),
+	)
+	css := s.ToCSS()
+	class := "." + s.GetClassName()
+
+	for _, want := range []string{
+		class + " h2 a, " + class + " h2 span, " + class + " h3 a, " + class + " h3 span {",
+		class + " h2:hover, " + class + " h3:hover {",
+		class + `[data-label="a&b"] {`,
+	} {
+		if !strings.Contains(css, want) {
+			t.Errorf("CSS has no %q:\n%s", want, css)
+		}
+	}
+}
+
+func TestCSSString(t *testing.T) {
+	tests := map[string]string{
+		`plain`:       `"plain"`,
+		`say "hi"`:    `"say \"hi\""`,
+		`back\slash`:  `"back\\slash"`,
+		"two\nlines":  `"two\A lines"`,
+		"tab\tbell\a": `"tab\9 bell\7 "`,
+		"nul\x00":     "\"nul\uFFFD\"",
+		"→ é":         `"→ é"`,
+	}
+	for value, want := range tests {
+		if got := cssString(value); got != want {
+			t.Errorf("cssString(%q) = %s, want %s", value, got, want)
+		}
+	}
+}
+
+func keys[V any](m map[string]V) []string {
+	result := make([]string, 0, len(m))
+	for key := range m {
+		result = append(result, key)
+	}
+	return result
+}
@@ -1,5 +1,3 @@
-//go:build js && wasm
-
 // precompilation.go is a file that contains the precompilation framework
 // it is used to precompile the styles and cache the CSS
 // It can significantly improve the performance of the application
@@ -9,6 +7,7 @@ package style
 
 import (
 	"fmt"
+	"io"
 	"log"
 	"time"
 )
@@ -221,6 +220,24 @@ func (e *PrecompilationEngine) calculateCacheHitRate() float64 {
 	return 0.0
 }
 
+// ExportCSS writes the CSS of every registered style to w, each class once, in registration order
+// It does not depend on the browser: the build runs it to extract the styles in a static file
+func (e *PrecompilationEngine) ExportCSS(w io.Writer) error {
+	exported := make(map[string]bool, len(e.styles))
+	for _, style := range e.styles {
+		className := style.GetClassName()
+		if exported[className] {
+			continue
+		}
+		exported[className] = true
+
+		if _, err := io.WriteString(w, style.ToCSS()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Clear removes all precompiled styles
 func (e *PrecompilationEngine) Clear() {
 	e.styles = e.styles[:0]
@@ -264,6 +281,12 @@ func RunPrecompilation() {
 	globalPrecompiler.RunAllPrecompilation()
 }
 
+// ExportCSS writes the CSS of the styles registered in the global precompiler to w
+// `vortex build` uses it to generate the static app.css of the application
+func ExportCSS(w io.Writer) error {
+	return globalPrecompiler.ExportCSS(w)
+}
+
 // IsPrecompiled checks if a style is precompiled in the global engine
 func IsPrecompiled(style *Style) bool {
 	return globalPrecompiler.IsStyleCompiled(style)
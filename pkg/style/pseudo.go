@@ -1,5 +1,3 @@
-//go:build js && wasm
-
 // Package style provides type-safe CSS styling for Vortex components.
 // This file contains the functions to apply the pseudo-classes to a style.
 // It is used to apply the pseudo-classes to a style.
@@ -33,6 +31,7 @@ func OnHover(properties ...StyleOption) StyleOption {
 		// Create a temporary style object to collect the hover properties
 		hoverStyle := New(properties...)
 		s.Pseudos[":hover"] = hoverStyle.Base
+		s.adoptKeyframes(hoverStyle)
 	}
 }
 
@@ -41,6 +40,7 @@ func OnActive(properties ...StyleOption) StyleOption {
 	return func(s *Style) {
 		activeStyle := New(properties...)
 		s.Pseudos[":active"] = activeStyle.Base
+		s.adoptKeyframes(activeStyle)
 	}
 }
 
@@ -49,6 +49,7 @@ func OnFocus(properties ...StyleOption) StyleOption {
 	return func(s *Style) {
 		focusStyle := New(properties...)
 		s.Pseudos[":focus"] = focusStyle.Base
+		s.adoptKeyframes(focusStyle)
 	}
 }
 
@@ -57,6 +58,7 @@ func OnFocusWithin(properties ...StyleOption) StyleOption {
 	return func(s *Style) {
 		focusWithinStyle := New(properties...)
 		s.Pseudos[":focus-within"] = focusWithinStyle.Base
+		s.adoptKeyframes(focusWithinStyle)
 	}
 }
 
@@ -65,5 +67,6 @@ func OnFocusVisible(properties ...StyleOption) StyleOption {
 	return func(s *Style) {
 		focusVisibleStyle := New(properties...)
 		s.Pseudos[":focus-visible"] = focusVisibleStyle.Base
+		s.adoptKeyframes(focusVisibleStyle)
 	}
 }
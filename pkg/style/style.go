@@ -1,5 +1,3 @@
-//go:build js && wasm
-
 // Package style provides type-safe CSS styling for Vortex components.
 //
 // This package includes comprehensive styling utilities organized into logical groups:
@@ -11,6 +9,7 @@
 //   - Interactive: Hover, focus, active pseudo-classes (see pseudo.go)
 //   - Layout: Position, overflow, cursor, z-index (see misc.go)
 //   - Media Queries: Responsive design utilities (see media_query.go)
+//   - Nested rules: Children, descendants, attributes, pseudo-elements (see nested.go)
 //
 // Basic Usage:
 //
@@ -44,12 +43,17 @@ type Property map[string]string
 // Style is the object that contains the full definition of a the style of a component
 type Style struct {
 	Base         Property
-	Pseudos      map[string]Property // ":hover", ":active", ":focus", etc.
-	MediaQueries map[string]Property // "screen and (max-width: 768px)", "screen and (min-width: 769px)"
-
-	// For caching
+	Pseudos      map[string]Property   // ":hover", ":active", ":focus", etc.
+	MediaQueries map[string]Property   // "screen and (max-width: 768px)", "screen and (min-width: 769px)"
+	Nested       map[string]*Style     // "& > h2", "&::before", "&[aria-expanded=\"true\"]" (see nested.go)
+	Queries      map[string]*Style     // Full styles under an at-rule: "@media print", "@container (min-width: 400px)"
+	Keyframes    map[string]*Keyframes // Keyframes run by the animations of the style, by name (see keyframes.go)
+
+	// For caching, the class name and CSS are valid while the content is unchanged (see GetClassName)
+	content   string
 	className string
 	css       string
+	dirty     bool // Set by Update: the content is generated again on the next GetClassName
 }
 
 // StyleOption is a function that modifies the style
@@ -61,6 +65,9 @@ func New(options ...StyleOption) *Style {
 		Base:         make(Property),
 		Pseudos:      make(map[string]Property),
 		MediaQueries: make(map[string]Property),
+		Nested:       make(map[string]*Style),
+		Queries:      make(map[string]*Style),
+		Keyframes:    make(map[string]*Keyframes),
 	}
 	for _, option := range options {
 		s.Update(option)
@@ -92,6 +99,9 @@ func Extend(baseStyle *Style, options ...StyleOption) *Style {
 		Base:         make(Property),
 		Pseudos:      make(map[string]Property),
 		MediaQueries: make(map[string]Property),
+		Nested:       make(map[string]*Style),
+		Queries:      make(map[string]*Style),
+		Keyframes:    make(map[string]*Keyframes),
 	}
 
 	// 2. Deep copy the base properties
@@ -117,15 +127,34 @@ func Extend(baseStyle *Style, options ...StyleOption) *Style {
 		s.MediaQueries[mediaQuery] = newMediaProps
 	}
 
-	// 5. Apply the options
+	// 5. Deep copy the nested rules
+	for selector, nested := range baseStyle.Nested {
+		s.Nested[selector] = Extend(nested)
+	}
+
+	// 6. Deep copy the queries
+	for atRule, query := range baseStyle.Queries {
+		s.Queries[atRule] = Extend(query)
+	}
+
+	// 7. Share the keyframes, they are immutable
+	for name, keyframes := range baseStyle.Keyframes {
+		s.Keyframes[name] = keyframes
+	}
+
+	// 8. Apply the options
 	s.Update(options...)
 	return s
 }
 
+// Update applies the options to the style
+// An element rendered with the style gets the new class on its next patch
+// After editing the maps of the style directly, call Update without options so the class follows
 func (s *Style) Update(options ...StyleOption) {
 	for _, option := range options {
 		option(s)
 	}
+	s.dirty = true
 }
 
 // Function to apply a style which is not in the function already defined
@@ -1,25 +1,392 @@
-//go:build js && wasm
-
 // Package style provides type-safe CSS styling for Vortex components.
-// This file contains the functions to apply the theme to a style.
-// It is used to apply the theme to a style.
+// This file contains the theming system: design tokens written as CSS custom properties.
 //
-// The style.Theme function act as a wrapper for the whole App VDOM tree
-// It is used to apply the theme to the whole App VDOM tree
-// The theme is applied to the whole App VDOM tree through the style.
-// For example, to apply a theme to the whole App VDOM tree, you can use the following code:
+// A Theme holds the design tokens of the application (colors, spacing scale, radii,
+// typography and shadows). It is written out as CSS custom properties (--vtx-primary,
+// --vtx-space-md, ...), and the styles reference the tokens instead of raw values:
 //
-//   theme := style.New(
-//       style.Theme(style.ThemeLight),
+//   card := style.New(
+//       style.BackgroundColor(style.ThemeColor(style.TokenSurface)),
+//       style.Padding(style.PaddingAll, style.ThemeSpace(style.TokenSpaceMD)),
+//       style.BorderRadius(style.ThemeRadius(style.TokenRadiusMD)),
+//       style.BoxShadowToken(style.TokenShadowSM),
 //   )
 //
+// The class of a style only contains var(--vtx-...) references: switching the theme
+// only rewrites the custom properties, no class is generated again.
 //
 // Basic Usage:
 //
-//   style := style.New(
-//       style.Theme(style.ThemeLight),
-//   )
+//   // In the page, before the first paint
+//   css := style.ThemeLight.ToCSS(":root")
+//
+//   // At runtime, in the browser
+//   r.SetTheme(style.ThemeDark)
 //
 // For more information, see the style package documentation
 
 package style
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TokenPrefix is the prefix of the CSS custom properties of the theme tokens
+const TokenPrefix = "--vtx-"
+
+// TokenVar returns the CSS reference to a theme token, such as var(--vtx-primary)
+func TokenVar(token string) string {
+	return "var(" + TokenPrefix + token + ")"
+}
+
+// --- Tokens
+
+// ColorToken is the name of a color of the theme
+type ColorToken string
+
+const (
+	TokenPrimary    ColorToken = "primary"
+	TokenSecondary  ColorToken = "secondary"
+	TokenAccent     ColorToken = "accent"
+	TokenBackground ColorToken = "background"
+	TokenSurface    ColorToken = "surface"
+	TokenText       ColorToken = "text"
+	TokenTextMuted  ColorToken = "text-muted"
+	TokenBorder     ColorToken = "border"
+	TokenSuccess    ColorToken = "success"
+	TokenWarning    ColorToken = "warning"
+	TokenError      ColorToken = "error"
+)
+
+// SpaceToken is the name of a step of the spacing scale of the theme
+type SpaceToken string
+
+const (
+	TokenSpaceXS SpaceToken = "space-xs"
+	TokenSpaceSM SpaceToken = "space-sm"
+	TokenSpaceMD SpaceToken = "space-md"
+	TokenSpaceLG SpaceToken = "space-lg"
+	TokenSpaceXL SpaceToken = "space-xl"
+)
+
+// RadiusToken is the name of a border radius of the theme
+type RadiusToken string
+
+const (
+	TokenRadiusSM   RadiusToken = "radius-sm"
+	TokenRadiusMD   RadiusToken = "radius-md"
+	TokenRadiusLG   RadiusToken = "radius-lg"
+	TokenRadiusFull RadiusToken = "radius-full"
+)
+
+// FontSizeToken is the name of a font size of the theme
+type FontSizeToken string
+
+const (
+	TokenFontSizeSM FontSizeToken = "font-size-sm"
+	TokenFontSizeMD FontSizeToken = "font-size-md"
+	TokenFontSizeLG FontSizeToken = "font-size-lg"
+	TokenFontSizeXL FontSizeToken = "font-size-xl"
+)
+
+// FontToken is the name of a font family of the theme
+type FontToken string
+
+const (
+	TokenFontBody    FontToken = "font-body"
+	TokenFontHeading FontToken = "font-heading"
+	TokenFontMono    FontToken = "font-mono"
+)
+
+// ShadowToken is the name of a shadow of the theme
+type ShadowToken string
+
+const (
+	TokenShadowSM ShadowToken = "shadow-sm"
+	TokenShadowMD ShadowToken = "shadow-md"
+	TokenShadowLG ShadowToken = "shadow-lg"
+)
+
+// ThemeColor returns a color referencing a color token
+func ThemeColor(token ColorToken) ColorValue {
+	return ColorValue{Value: TokenVar(string(token))}
+}
+
+// ThemeSpace returns a length referencing a step of the spacing scale
+func ThemeSpace(token SpaceToken) LengthValue {
+	return LengthValue{token: string(token)}
+}
+
+// ThemeRadius returns a length referencing a border radius
+func ThemeRadius(token RadiusToken) LengthValue {
+	return LengthValue{token: string(token)}
+}
+
+// ThemeFontSize returns a length referencing a font size
+func ThemeFontSize(token FontSizeToken) LengthValue {
+	return LengthValue{token: string(token)}
+}
+
+// FontFamilyToken applies a font family of the theme
+func FontFamilyToken(token FontToken) StyleOption {
+	return func(s *Style) {
+		s.Base["font-family"] = TokenVar(string(token))
+	}
+}
+
+// BoxShadowToken applies a shadow of the theme
+func BoxShadowToken(token ShadowToken) StyleOption {
+	return func(s *Style) {
+		s.Base["box-shadow"] = TokenVar(string(token))
+	}
+}
+
+// --- Theme
+
+// ThemeColors are the colors of a theme
+type ThemeColors struct {
+	Primary    ColorValue
+	Secondary  ColorValue
+	Accent     ColorValue
+	Background ColorValue
+	Surface    ColorValue
+	Text       ColorValue
+	TextMuted  ColorValue
+	Border     ColorValue
+	Success    ColorValue
+	Warning    ColorValue
+	Error      ColorValue
+}
+
+// ThemeSpacing is the spacing scale of a theme
+type ThemeSpacing struct {
+	XS LengthValue
+	SM LengthValue
+	MD LengthValue
+	LG LengthValue
+	XL LengthValue
+}
+
+// ThemeRadii are the border radii of a theme
+type ThemeRadii struct {
+	SM   LengthValue
+	MD   LengthValue
+	LG   LengthValue
+	Full LengthValue
+}
+
+// ThemeTypography are the fonts of a theme
+type ThemeTypography struct {
+	FontBody    string
+	FontHeading string
+	FontMono    string
+
+	FontSizeSM LengthValue
+	FontSizeMD LengthValue
+	FontSizeLG LengthValue
+	FontSizeXL LengthValue
+}
+
+// ThemeShadows are the shadows of a theme, each one can stack several shadows
+type ThemeShadows struct {
+	SM []BoxShadowValue
+	MD []BoxShadowValue
+	LG []BoxShadowValue
+}
+
+// Theme is the full set of design tokens of an application
+type Theme struct {
+	Name       string // Written in the data-vtx-theme attribute of the page when applied
+	Colors     ThemeColors
+	Spacing    ThemeSpacing
+	Radii      ThemeRadii
+	Typography ThemeTypography
+	Shadows    ThemeShadows
+}
+
+// Variables returns the CSS custom properties of the theme, by name
+// Ex: "--vtx-primary" -> "#2196f3"
+func (t *Theme) Variables() Property {
+	vars := make(Property)
+	set := func(token string, value string) {
+		if value != "" {
+			vars[TokenPrefix+token] = value
+		}
+	}
+
+	colors := map[ColorToken]ColorValue{
+		TokenPrimary:    t.Colors.Primary,
+		TokenSecondary:  t.Colors.Secondary,
+		TokenAccent:     t.Colors.Accent,
+		TokenBackground: t.Colors.Background,
+		TokenSurface:    t.Colors.Surface,
+		TokenText:       t.Colors.Text,
+		TokenTextMuted:  t.Colors.TextMuted,
+		TokenBorder:     t.Colors.Border,
+		TokenSuccess:    t.Colors.Success,
+		TokenWarning:    t.Colors.Warning,
+		TokenError:      t.Colors.Error,
+	}
+	for token, color := range colors {
+		set(string(token), color.String())
+	}
+
+	lengths := map[string]LengthValue{
+		string(TokenSpaceXS):    t.Spacing.XS,
+		string(TokenSpaceSM):    t.Spacing.SM,
+		string(TokenSpaceMD):    t.Spacing.MD,
+		string(TokenSpaceLG):    t.Spacing.LG,
+		string(TokenSpaceXL):    t.Spacing.XL,
+		string(TokenRadiusSM):   t.Radii.SM,
+		string(TokenRadiusMD):   t.Radii.MD,
+		string(TokenRadiusLG):   t.Radii.LG,
+		string(TokenRadiusFull): t.Radii.Full,
+		string(TokenFontSizeSM): t.Typography.FontSizeSM,
+		string(TokenFontSizeMD): t.Typography.FontSizeMD,
+		string(TokenFontSizeLG): t.Typography.FontSizeLG,
+		string(TokenFontSizeXL): t.Typography.FontSizeXL,
+	}
+	for token, length := range lengths {
+		if length.isSet() {
+			set(token, length.String())
+		}
+	}
+
+	set(string(TokenFontBody), t.Typography.FontBody)
+	set(string(TokenFontHeading), t.Typography.FontHeading)
+	set(string(TokenFontMono), t.Typography.FontMono)
+
+	set(string(TokenShadowSM), shadowsToCSS(t.Shadows.SM))
+	set(string(TokenShadowMD), shadowsToCSS(t.Shadows.MD))
+	set(string(TokenShadowLG), shadowsToCSS(t.Shadows.LG))
+
+	return vars
+}
+
+// ToCSS writes the custom properties of the theme in a rule for the selector
+// ":root" makes it the theme of the whole page, a class or an attribute selector scopes it
+func (t *Theme) ToCSS(selector string) string {
+	return fmt.Sprintf("%s {%s}\n", selector, propsToCSS(t.Variables()))
+}
+
+// Extend returns a copy of the theme with the given changes
+//
+// Usage examples :
+//
+//	brand := style.ThemeLight.Extend(func(t *style.Theme) {
+//	    t.Name = "brand"
+//	    t.Colors.Primary = style.HEX("#ff5722")
+//	})
+func (t *Theme) Extend(changes func(t *Theme)) *Theme {
+	copied := *t
+	copied.Shadows = ThemeShadows{
+		SM: append([]BoxShadowValue(nil), t.Shadows.SM...),
+		MD: append([]BoxShadowValue(nil), t.Shadows.MD...),
+		LG: append([]BoxShadowValue(nil), t.Shadows.LG...),
+	}
+	changes(&copied)
+	return &copied
+}
+
+// shadowsToCSS joins several shadows in a single box-shadow value
+func shadowsToCSS(shadows []BoxShadowValue) string {
+	values := make([]string, len(shadows))
+	for i, shadow := range shadows {
+		values[i] = shadow.String()
+		if shadow.IsInset {
+			values[i] = "inset " + values[i]
+		}
+	}
+	return strings.Join(values, ", ")
+}
+
+// --- Built-in themes
+
+// ThemeLight is the built-in light theme
+var ThemeLight = &Theme{
+	Name: "light",
+	Colors: ThemeColors{
+		Primary:    HEX("#2196f3"),
+		Secondary:  HEX("#6c757d"),
+		Accent:     HEX("#ff9800"),
+		Background: HEX("#ffffff"),
+		Surface:    HEX("#f8f9fa"),
+		Text:       HEX("#2c3e50"),
+		TextMuted:  HEX("#6c757d"),
+		Border:     HEX("#dee2e6"),
+		Success:    HEX("#4caf50"),
+		Warning:    HEX("#ffc107"),
+		Error:      HEX("#f44336"),
+	},
+	Spacing: defaultSpacing,
+	Radii:   defaultRadii,
+	Typography: ThemeTypography{
+		FontBody:    defaultFontFamily,
+		FontHeading: defaultFontFamily,
+		FontMono:    defaultMonoFontFamily,
+		FontSizeSM:  Rem(0.875),
+		FontSizeMD:  Rem(1),
+		FontSizeLG:  Rem(1.25),
+		FontSizeXL:  Rem(2),
+	},
+	Shadows: ThemeShadows{
+		SM: []BoxShadowValue{{OffsetX: Px(0), OffsetY: Px(1), BlurRadius: Px(3), SpreadRadius: Px(0), Color: RGBA(0, 0, 0, 0.12)}},
+		MD: []BoxShadowValue{{OffsetX: Px(0), OffsetY: Px(4), BlurRadius: Px(12), SpreadRadius: Px(0), Color: RGBA(0, 0, 0, 0.12)}},
+		LG: []BoxShadowValue{{OffsetX: Px(0), OffsetY: Px(10), BlurRadius: Px(30), SpreadRadius: Px(0), Color: RGBA(0, 0, 0, 0.15)}},
+	},
+}
+
+// ThemeDark is the built-in dark theme
+var ThemeDark = &Theme{
+	Name: "dark",
+	Colors: ThemeColors{
+		Primary:    HEX("#64b5f6"),
+		Secondary:  HEX("#adb5bd"),
+		Accent:     HEX("#ffb74d"),
+		Background: HEX("#121212"),
+		Surface:    HEX("#1e1e1e"),
+		Text:       HEX("#e9ecef"),
+		TextMuted:  HEX("#adb5bd"),
+		Border:     HEX("#343a40"),
+		Success:    HEX("#81c784"),
+		Warning:    HEX("#ffd54f"),
+		Error:      HEX("#e57373"),
+	},
+	Spacing: defaultSpacing,
+	Radii:   defaultRadii,
+	Typography: ThemeTypography{
+		FontBody:    defaultFontFamily,
+		FontHeading: defaultFontFamily,
+		FontMono:    defaultMonoFontFamily,
+		FontSizeSM:  Rem(0.875),
+		FontSizeMD:  Rem(1),
+		FontSizeLG:  Rem(1.25),
+		FontSizeXL:  Rem(2),
+	},
+	Shadows: ThemeShadows{
+		SM: []BoxShadowValue{{OffsetX: Px(0), OffsetY: Px(1), BlurRadius: Px(3), SpreadRadius: Px(0), Color: RGBA(0, 0, 0, 0.5)}},
+		MD: []BoxShadowValue{{OffsetX: Px(0), OffsetY: Px(4), BlurRadius: Px(12), SpreadRadius: Px(0), Color: RGBA(0, 0, 0, 0.5)}},
+		LG: []BoxShadowValue{{OffsetX: Px(0), OffsetY: Px(10), BlurRadius: Px(30), SpreadRadius: Px(0), Color: RGBA(0, 0, 0, 0.6)}},
+	},
+}
+
+var (
+	defaultFontFamily     = "'Inter', -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif"
+	defaultMonoFontFamily = "'JetBrains Mono', 'Fira Code', Menlo, Consolas, monospace"
+
+	defaultSpacing = ThemeSpacing{
+		XS: Rem(0.25),
+		SM: Rem(0.5),
+		MD: Rem(1),
+		LG: Rem(1.5),
+		XL: Rem(3),
+	}
+
+	defaultRadii = ThemeRadii{
+		SM:   Px(4),
+		MD:   Px(8),
+		LG:   Px(16),
+		Full: Px(9999),
+	}
+)
@@ -0,0 +1,22 @@
+package style
+
+import "testing"
+
+func TestThemeVariablesKeepZeroLengths(t *testing.T) {
+	theme := ThemeLight.Extend(func(t *Theme) {
+		t.Spacing.XS = Px(0)
+		t.Radii.SM = Rem(0)
+		t.Radii.Full = LengthValue{}
+	})
+	vars := theme.Variables()
+
+	if got := vars[TokenPrefix+string(TokenSpaceXS)]; got != Px(0).String() {
+		t.Errorf("space-xs = %q, want %q", got, Px(0).String())
+	}
+	if got := vars[TokenPrefix+string(TokenRadiusSM)]; got != Rem(0).String() {
+		t.Errorf("radius-sm = %q, want %q", got, Rem(0).String())
+	}
+	if got, ok := vars[TokenPrefix+string(TokenRadiusFull)]; ok {
+		t.Errorf("radius-full = %q, want no variable for an unset length", got)
+	}
+}
@@ -0,0 +1,569 @@
+// Package style provides type-safe CSS styling for Vortex components.
+// This file contains the typed builders of the transforms, the filters and the clip paths.
+// The transforms and the filters are lists of functions which can be composed and interpolated,
+// the animation package interpolates them function by function.
+//
+// Basic Usage:
+//
+//   style := style.New(
+//       style.Transform(style.Translate(style.Px(10), style.Px(0)), style.Rotate(style.Degrees(45))),
+//       style.Filter(style.Blur(style.Px(2)), style.Grayscale(0.5)),
+//       style.ClipPath(style.ClipCircle(style.Percent(50), style.Percent(50), style.Percent(50))),
+//   )
+//
+// All properties available are (table with css equivalent)
+// | Property | CSS Equivalent |
+// | -------- | -------------- |
+// | Transform | transform |
+// | Transform Origin | transform-origin |
+// | Filter | filter |
+// | Backdrop Filters | backdrop-filter |
+// | Clip Path | clip-path |
+//
+// For more information, see the style package documentation
+
+package style
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ===== ANGLE VALUES =====
+
+type AngleUnit string
+
+const (
+	UnitDeg  AngleUnit = "deg"
+	UnitRad  AngleUnit = "rad"
+	UnitTurn AngleUnit = "turn"
+)
+
+// AngleValue is a type that represents an angle
+// Usage examples :
+//
+//	style.Degrees(45)
+//	style.Turns(0.5)
+type AngleValue struct {
+	Value float64
+	Unit  AngleUnit
+}
+
+func (a AngleValue) String() string {
+	return formatNumber(a.Value) + string(a.Unit)
+}
+
+func (a AngleValue) Validate() error {
+	switch a.Unit {
+	case UnitDeg, UnitRad, UnitTurn:
+		return nil
+	}
+	return &ValidationError{Property: "angle", Value: a.String(), Reason: fmt.Sprintf("invalid unit '%s'", a.Unit)}
+}
+
+// Constructor functions for angle value
+func Degrees(value float64) AngleValue { return AngleValue{Value: value, Unit: UnitDeg} }
+func Radians(value float64) AngleValue { return AngleValue{Value: value, Unit: UnitRad} }
+func Turns(value float64) AngleValue   { return AngleValue{Value: value, Unit: UnitTurn} }
+
+// ===== FUNCTIONS =====
+
+// FunctionArg is an argument of a transform or filter function
+// A numeric argument is interpolated, a keyword argument (such as a color) is used as is
+type FunctionArg struct {
+	Value   float64
+	Unit    string
+	Keyword string
+}
+
+func (a FunctionArg) String() string {
+	if a.Keyword != "" {
+		return a.Keyword
+	}
+	return formatNumber(a.Value) + a.Unit
+}
+
+func numberArg(value float64) FunctionArg {
+	return FunctionArg{Value: value}
+}
+
+func angleArg(value AngleValue) FunctionArg {
+	validateCSSValue("angle", value)
+	return FunctionArg{Value: value.Value, Unit: string(value.Unit)}
+}
+
+// lengthArg keeps a length referring to a theme token as a keyword
+func lengthArg(value LengthValue) FunctionArg {
+	validateCSSValue("length", value)
+	if value.token != "" {
+		return FunctionArg{Keyword: value.String()}
+	}
+	return FunctionArg{Value: value.Value, Unit: string(value.Unit)}
+}
+
+func formatNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+func formatFunction(name string, args []FunctionArg, separator string) string {
+	parts := make([]string, len(args))
+	for i, arg := range args {
+		parts[i] = arg.String()
+	}
+	return name + "(" + strings.Join(parts, separator) + ")"
+}
+
+// degreesPer is the number of degrees in each angle unit
+var degreesPer = map[string]float64{
+	string(UnitDeg):  1,
+	string(UnitRad):  180 / math.Pi,
+	string(UnitTurn): 360,
+}
+
+// interpolateArgs interpolates the numeric arguments with the same unit
+// It reports false when the arguments cannot be interpolated one by one
+func interpolateArgs(from, to []FunctionArg, progress float64) ([]FunctionArg, bool) {
+	if len(from) != len(to) {
+		return nil, false
+	}
+	args := make([]FunctionArg, len(from))
+	for i := range from {
+		if from[i].Keyword != "" || to[i].Keyword != "" {
+			args[i] = from[i]
+			if progress >= 0.5 {
+				args[i] = to[i]
+			}
+			continue
+		}
+		// 0 is 0 in any unit, and the angles are converted to the unit of the target
+		value, unit := from[i].Value, from[i].Unit
+		if from[i].Unit != to[i].Unit {
+			fromDegrees, fromAngle := degreesPer[from[i].Unit]
+			toDegrees, toAngle := degreesPer[to[i].Unit]
+			switch {
+			case from[i].Value == 0:
+				unit = to[i].Unit
+			case fromAngle && toAngle:
+				value, unit = value*fromDegrees/toDegrees, to[i].Unit
+			case to[i].Value != 0:
+				return nil, false
+			}
+		}
+		args[i] = FunctionArg{Value: value + (to[i].Value-value)*progress, Unit: unit}
+	}
+	return args, true
+}
+
+// ===== TRANSFORM =====
+
+// TransformFunction is a function of a transform, such as translate(10px, 0px)
+type TransformFunction struct {
+	Name string
+	Args []FunctionArg
+}
+
+func (f TransformFunction) String() string {
+	return formatFunction(f.Name, f.Args, ", ")
+}
+
+// identity returns the function with the arguments leaving the element unchanged
+func (f TransformFunction) identity() TransformFunction {
+	args := make([]FunctionArg, len(f.Args))
+	for i, arg := range f.Args {
+		args[i] = FunctionArg{Unit: arg.Unit}
+	}
+	switch f.Name {
+	case "scale", "scaleX", "scaleY":
+		for i := range args {
+			args[i] = numberArg(1)
+		}
+	case "matrix":
+		args = []FunctionArg{numberArg(1), numberArg(0), numberArg(0), numberArg(1), numberArg(0), numberArg(0)}
+	}
+	return TransformFunction{Name: f.Name, Args: args}
+}
+
+// Translate moves the element
+func Translate(x, y LengthValue) TransformFunction {
+	return TransformFunction{Name: "translate", Args: []FunctionArg{lengthArg(x), lengthArg(y)}}
+}
+
+// TranslateX moves the element horizontally
+func TranslateX(x LengthValue) TransformFunction {
+	return TransformFunction{Name: "translateX", Args: []FunctionArg{lengthArg(x)}}
+}
+
+// TranslateY moves the element vertically
+func TranslateY(y LengthValue) TransformFunction {
+	return TransformFunction{Name: "translateY", Args: []FunctionArg{lengthArg(y)}}
+}
+
+// Scale resizes the element by the same factor on both axes
+func Scale(factor float64) TransformFunction {
+	return TransformFunction{Name: "scale", Args: []FunctionArg{numberArg(factor)}}
+}
+
+// ScaleXY resizes the element by a factor per axis
+func ScaleXY(x, y float64) TransformFunction {
+	return TransformFunction{Name: "scale", Args: []FunctionArg{numberArg(x), numberArg(y)}}
+}
+
+// Rotate turns the element clockwise
+func Rotate(angle AngleValue) TransformFunction {
+	return TransformFunction{Name: "rotate", Args: []FunctionArg{angleArg(angle)}}
+}
+
+// Skew slants the element on both axes
+func Skew(x, y AngleValue) TransformFunction {
+	return TransformFunction{Name: "skew", Args: []FunctionArg{angleArg(x), angleArg(y)}}
+}
+
+// SkewX slants the element horizontally
+func SkewX(x AngleValue) TransformFunction {
+	return TransformFunction{Name: "skewX", Args: []FunctionArg{angleArg(x)}}
+}
+
+// SkewY slants the element vertically
+func SkewY(y AngleValue) TransformFunction {
+	return TransformFunction{Name: "skewY", Args: []FunctionArg{angleArg(y)}}
+}
+
+// Matrix applies the 2D transformation matrix(a, b, c, d, tx, ty)
+func Matrix(a, b, c, d, tx, ty float64) TransformFunction {
+	return TransformFunction{Name: "matrix", Args: []FunctionArg{
+		numberArg(a), numberArg(b), numberArg(c), numberArg(d), numberArg(tx), numberArg(ty),
+	}}
+}
+
+// TransformValue is a type that represents a transform, its functions applied in order
+// Usage examples :
+//
+//	style.NewTransform(style.Translate(style.Px(10), style.Px(0)), style.Scale(1.2))
+type TransformValue []TransformFunction
+
+// NewTransform returns the transform of the given functions
+func NewTransform(functions ...TransformFunction) TransformValue {
+	return TransformValue(functions)
+}
+
+// Then returns the transform followed by the given functions
+func (t TransformValue) Then(functions ...TransformFunction) TransformValue {
+	return append(t[:len(t):len(t)], functions...)
+}
+
+func (t TransformValue) String() string {
+	if len(t) == 0 {
+		return "none"
+	}
+	parts := make([]string, len(t))
+	for i, f := range t {
+		parts[i] = f.String()
+	}
+	return strings.Join(parts, " ")
+}
+
+var transformArgCounts = map[string][]int{
+	"translate":  {1, 2},
+	"translateX": {1},
+	"translateY": {1},
+	"scale":      {1, 2},
+	"scaleX":     {1},
+	"scaleY":     {1},
+	"rotate":     {1},
+	"skew":       {1, 2},
+	"skewX":      {1},
+	"skewY":      {1},
+	"matrix":     {6},
+}
+
+func (t TransformValue) Validate() error {
+	for _, f := range t {
+		counts, known := transformArgCounts[f.Name]
+		if !known {
+			return &ValidationError{Property: "transform", Value: t.String(), Reason: fmt.Sprintf("unknown function '%s'", f.Name)}
+		}
+		if !containsInt(counts, len(f.Args)) {
+			return &ValidationError{Property: "transform", Value: t.String(), Reason: fmt.Sprintf("wrong number of arguments for '%s'", f.Name)}
+		}
+	}
+	return nil
+}
+
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Interpolate returns the transform at the progress (0 to 1) from t to the target
+// The functions are interpolated one by one when both lists have the same functions,
+// a missing function counting as its identity (such as scale(1)). The angles are converted to the
+// unit of the target, other units must match. Otherwise the transform switches to the target halfway.
+func (t TransformValue) Interpolate(to TransformValue, progress float64) TransformValue {
+	from := t
+	for len(from) < len(to) {
+		from = from.Then(to[len(from)].identity())
+	}
+	for len(to) < len(from) {
+		to = to.Then(from[len(to)].identity())
+	}
+
+	result := make(TransformValue, len(from))
+	for i := range from {
+		args, ok := interpolateArgs(from[i].Args, to[i].Args, progress)
+		if from[i].Name != to[i].Name || !ok {
+			if progress >= 0.5 {
+				return to
+			}
+			return from
+		}
+		result[i] = TransformFunction{Name: from[i].Name, Args: args}
+	}
+	return result
+}
+
+// Transform applies the transform functions to the element, in order
+// Usage examples :
+//
+//	style.Transform(style.TranslateY(style.Px(-4)), style.Scale(1.05))
+func Transform(functions ...TransformFunction) StyleOption {
+	value := NewTransform(functions...)
+	validateCSSValue("transform", value)
+	return func(s *Style) {
+		s.Base["transform"] = value.String()
+	}
+}
+
+// TransformOrigin sets the point the transform is applied around
+// Usage examples :
+//
+//	style.TransformOrigin(style.Percent(50), style.Percent(100))
+func TransformOrigin(x, y LengthValue) StyleOption {
+	return func(s *Style) {
+		s.Base["transform-origin"] = x.String() + " " + y.String()
+	}
+}
+
+// ===== FILTER =====
+
+// FilterFunction is a function of a filter, such as blur(2px)
+type FilterFunction struct {
+	Name string
+	Args []FunctionArg
+}
+
+func (f FilterFunction) String() string {
+	return formatFunction(f.Name, f.Args, " ")
+}
+
+// identity returns the function with the arguments leaving the element unchanged
+func (f FilterFunction) identity() FilterFunction {
+	args := make([]FunctionArg, len(f.Args))
+	for i, arg := range f.Args {
+		args[i] = FunctionArg{Unit: arg.Unit, Keyword: arg.Keyword}
+	}
+	switch f.Name {
+	case "brightness", "contrast", "opacity", "saturate":
+		args = []FunctionArg{numberArg(1)}
+	case "drop-shadow":
+		args[len(args)-1] = FunctionArg{Keyword: ColorTransparent.String()}
+	}
+	return FilterFunction{Name: f.Name, Args: args}
+}
+
+// Blur blurs the element by the radius
+func Blur(radius LengthValue) FilterFunction {
+	return FilterFunction{Name: "blur", Args: []FunctionArg{lengthArg(radius)}}
+}
+
+// Brightness scales the brightness, 1 leaves the element unchanged
+func Brightness(amount float64) FilterFunction {
+	return FilterFunction{Name: "brightness", Args: []FunctionArg{numberArg(amount)}}
+}
+
+// Contrast scales the contrast, 1 leaves the element unchanged
+func Contrast(amount float64) FilterFunction {
+	return FilterFunction{Name: "contrast", Args: []FunctionArg{numberArg(amount)}}
+}
+
+// Grayscale converts to grayscale, from 0 (unchanged) to 1 (fully gray)
+func Grayscale(amount float64) FilterFunction {
+	return FilterFunction{Name: "grayscale", Args: []FunctionArg{numberArg(amount)}}
+}
+
+// HueRotate rotates the hues by the angle
+func HueRotate(angle AngleValue) FilterFunction {
+	return FilterFunction{Name: "hue-rotate", Args: []FunctionArg{angleArg(angle)}}
+}
+
+// Invert inverts the colors, from 0 (unchanged) to 1 (fully inverted)
+func Invert(amount float64) FilterFunction {
+	return FilterFunction{Name: "invert", Args: []FunctionArg{numberArg(amount)}}
+}
+
+// FilterOpacity makes the element transparent, from 1 (unchanged) to 0 (invisible)
+func FilterOpacity(amount float64) FilterFunction {
+	return FilterFunction{Name: "opacity", Args: []FunctionArg{numberArg(amount)}}
+}
+
+// Saturate scales the saturation, 1 leaves the element unchanged
+func Saturate(amount float64) FilterFunction {
+	return FilterFunction{Name: "saturate", Args: []FunctionArg{numberArg(amount)}}
+}
+
+// Sepia converts to sepia, from 0 (unchanged) to 1 (fully sepia)
+func Sepia(amount float64) FilterFunction {
+	return FilterFunction{Name: "sepia", Args: []FunctionArg{numberArg(amount)}}
+}
+
+// DropShadow draws a shadow following the shape of the element
+func DropShadow(x, y, blur LengthValue, color ColorValue) FilterFunction {
+	validateCSSValue("drop-shadow", color)
+	return FilterFunction{Name: "drop-shadow", Args: []FunctionArg{
+		lengthArg(x), lengthArg(y), lengthArg(blur), {Keyword: color.String()},
+	}}
+}
+
+// FilterValue is a type that represents a filter, its functions applied in order
+// Usage examples :
+//
+//	style.NewFilter(style.Blur(style.Px(4)), style.Brightness(0.8))
+type FilterValue []FilterFunction
+
+// NewFilter returns the filter of the given functions
+func NewFilter(functions ...FilterFunction) FilterValue {
+	return FilterValue(functions)
+}
+
+func (f FilterValue) String() string {
+	if len(f) == 0 {
+		return "none"
+	}
+	parts := make([]string, len(f))
+	for i, function := range f {
+		parts[i] = function.String()
+	}
+	return strings.Join(parts, " ")
+}
+
+func (f FilterValue) Validate() error {
+	for _, function := range f {
+		for _, arg := range function.Args {
+			if arg.Keyword == "" && arg.Value < 0 && function.Name != "hue-rotate" && function.Name != "drop-shadow" {
+				return &ValidationError{Property: "filter", Value: f.String(), Reason: fmt.Sprintf("negative argument for '%s'", function.Name)}
+			}
+		}
+	}
+	return nil
+}
+
+// Interpolate returns the filter at the progress (0 to 1) from f to the target
+// It follows the same rules as TransformValue.Interpolate
+func (f FilterValue) Interpolate(to FilterValue, progress float64) FilterValue {
+	from := f
+	for len(from) < len(to) {
+		from = append(from[:len(from):len(from)], to[len(from)].identity())
+	}
+	for len(to) < len(from) {
+		to = append(to[:len(to):len(to)], from[len(to)].identity())
+	}
+
+	result := make(FilterValue, len(from))
+	for i := range from {
+		args, ok := interpolateArgs(from[i].Args, to[i].Args, progress)
+		if from[i].Name != to[i].Name || !ok {
+			if progress >= 0.5 {
+				return to
+			}
+			return from
+		}
+		result[i] = FilterFunction{Name: from[i].Name, Args: args}
+	}
+	return result
+}
+
+// Filter applies the filter functions to the element, in order
+// Usage examples :
+//
+//	style.Filter(style.Grayscale(1))
+//	style.OnHover(style.Filter(style.Grayscale(0)))
+func Filter(functions ...FilterFunction) StyleOption {
+	value := NewFilter(functions...)
+	validateCSSValue("filter", value)
+	return func(s *Style) {
+		s.Base["filter"] = value.String()
+	}
+}
+
+// BackdropFilters applies the filter functions to the area behind the element
+// Usage examples :
+//
+//	style.BackdropFilters(style.Blur(style.Px(20)), style.Saturate(1.8))
+func BackdropFilters(functions ...FilterFunction) StyleOption {
+	value := NewFilter(functions...)
+	validateCSSValue("backdrop-filter", value)
+	return func(s *Style) {
+		s.Base["backdrop-filter"] = value.String()
+	}
+}
+
+// ===== CLIP PATH =====
+
+// ClipPathValue is a type that represents the shape an element is clipped to
+// Usage examples :
+//
+//	style.ClipInset(style.Px(10))
+//	style.ClipCircle(style.Percent(50), style.Percent(50), style.Percent(50))
+//	style.ClipPolygon(style.Point{X: style.Percent(50), Y: style.Percent(0)}, ...)
+type ClipPathValue string
+
+func (c ClipPathValue) String() string {
+	return string(c)
+}
+
+func (c ClipPathValue) Validate() error {
+	return ValidateCSS("clip-path", string(c))
+}
+
+const ClipPathNone ClipPathValue = "none"
+
+// Point is a point of a polygon, relative to the element
+type Point struct {
+	X, Y LengthValue
+}
+
+// ClipInset clips the element to a rectangle inset by the given lengths, as for a margin
+func ClipInset(lengths ...LengthValue) ClipPathValue {
+	return ClipPathValue("inset(" + strings.Join(CSSValuesToString(lengths...), " ") + ")")
+}
+
+// ClipCircle clips the element to a circle of the radius centered on (x, y)
+func ClipCircle(radius, x, y LengthValue) ClipPathValue {
+	return ClipPathValue(fmt.Sprintf("circle(%s at %s %s)", radius, x, y))
+}
+
+// ClipEllipse clips the element to an ellipse of the radiuses centered on (x, y)
+func ClipEllipse(radiusX, radiusY, x, y LengthValue) ClipPathValue {
+	return ClipPathValue(fmt.Sprintf("ellipse(%s %s at %s %s)", radiusX, radiusY, x, y))
+}
+
+// ClipPolygon clips the element to the polygon of the points
+func ClipPolygon(points ...Point) ClipPathValue {
+	parts := make([]string, len(points))
+	for i, point := range points {
+		parts[i] = point.X.String() + " " + point.Y.String()
+	}
+	return ClipPathValue("polygon(" + strings.Join(parts, ", ") + ")")
+}
+
+// ClipPath clips the element to the shape
+func ClipPath(value ClipPathValue) StyleOption {
+	return func(s *Style) {
+		s.Base["clip-path"] = value.String()
+	}
+}
@@ -0,0 +1,183 @@
+package style
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTransformInterpolate(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to TransformValue
+		progress float64
+		want     string
+	}{
+		{"same functions", NewTransform(Translate(Px(0), Px(10))), NewTransform(Translate(Px(20), Px(30))), 0.25,
+			"translate(5px, 15px)"},
+		{"shorter from padded", NewTransform(TranslateY(Px(0))), NewTransform(TranslateY(Px(-40)), Scale(2), Rotate(Degrees(90))), 0.5,
+			"translateY(-20px) scale(1.5) rotate(45deg)"},
+		{"shorter target padded", NewTransform(Scale(2), Skew(Degrees(10), Degrees(20))), NewTransform(), 0.5,
+			"scale(1.5) skew(5deg, 10deg)"},
+		{"matrix padded", NewTransform(), NewTransform(Matrix(2, 0, 0, 2, 10, 0)), 0.5,
+			"matrix(1.5, 0, 0, 1.5, 5, 0)"},
+		{"zero in any unit", NewTransform(TranslateX(Px(0))), NewTransform(TranslateX(Percent(50))), 0.5,
+			"translateX(25%)"},
+		{"to zero in any unit", NewTransform(TranslateX(Percent(50))), NewTransform(TranslateX(Px(0))), 0.5,
+			"translateX(25%)"},
+		{"mismatched units before half", NewTransform(TranslateX(Px(10))), NewTransform(TranslateX(Percent(50))), 0.25,
+			"translateX(10px)"},
+		{"mismatched units after half", NewTransform(TranslateX(Px(10))), NewTransform(TranslateX(Percent(50))), 0.75,
+			"translateX(50%)"},
+		{"mismatched order before half", NewTransform(Scale(1), Rotate(Degrees(0))), NewTransform(Rotate(Degrees(90)), Scale(2)), 0.4,
+			"scale(1) rotate(0deg)"},
+		{"mismatched order after half", NewTransform(Scale(1), Rotate(Degrees(0))), NewTransform(Rotate(Degrees(90)), Scale(2)), 0.6,
+			"rotate(90deg) scale(2)"},
+		{"degrees to turns", NewTransform(Rotate(Degrees(90))), NewTransform(Rotate(Turns(0.75))), 0.5,
+			"rotate(0.5turn)"},
+		{"turns to degrees", NewTransform(Rotate(Turns(0.5))), NewTransform(Rotate(Degrees(270))), 0.5,
+			"rotate(225deg)"},
+		{"start", NewTransform(Scale(1), TranslateX(Px(4))), NewTransform(Scale(3), TranslateX(Px(8))), 0,
+			"scale(1) translateX(4px)"},
+		{"end", NewTransform(Scale(1), TranslateX(Px(4))), NewTransform(Scale(3), TranslateX(Px(8))), 1,
+			"scale(3) translateX(8px)"},
+		{"discrete start", NewTransform(Scale(1)), NewTransform(Rotate(Degrees(45))), 0,
+			"scale(1)"},
+		{"discrete end", NewTransform(Scale(1)), NewTransform(Rotate(Degrees(45))), 1,
+			"rotate(45deg)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.from.Interpolate(tt.to, tt.progress).String(); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRadiansInterpolate(t *testing.T) {
+	// Pi radians are converted to 180 degrees, the unit of the target
+	for _, tt := range []struct {
+		progress float64
+		want     float64
+	}{{0, 180}, {0.5, 90}, {1, 0}} {
+		got := NewTransform(Rotate(Radians(math.Pi))).Interpolate(NewTransform(Rotate(Degrees(0))), tt.progress)
+		if value := got[0].Args[0]; value.Unit != "deg" || math.Abs(value.Value-tt.want) > 1e-9 {
+			t.Errorf("progress %v: got %s, want %vdeg", tt.progress, got, tt.want)
+		}
+	}
+}
+
+func TestFilterInterpolate(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to FilterValue
+		progress float64
+		want     string
+	}{
+		{"same functions", NewFilter(Blur(Px(0)), Grayscale(1)), NewFilter(Blur(Px(8)), Grayscale(0)), 0.25,
+			"blur(2px) grayscale(0.75)"},
+		{"shorter from padded", NewFilter(), NewFilter(Brightness(0.5), Blur(Px(4))), 0.5,
+			"brightness(0.75) blur(2px)"},
+		{"shorter target padded", NewFilter(FilterOpacity(0), HueRotate(Degrees(180))), NewFilter(), 0.5,
+			"opacity(0.5) hue-rotate(90deg)"},
+		{"hue in turns", NewFilter(HueRotate(Degrees(0))), NewFilter(HueRotate(Turns(1))), 0.5,
+			"hue-rotate(0.5turn)"},
+		{"shadow color switched halfway", NewFilter(), NewFilter(DropShadow(Px(0), Px(4), Px(8), ColorBlack)), 0.75,
+			"drop-shadow(0px 3px 6px " + ColorBlack.String() + ")"},
+		{"mismatched order", NewFilter(Blur(Px(1)), Sepia(1)), NewFilter(Sepia(0), Blur(Px(0))), 0.4,
+			"blur(1px) sepia(1)"},
+		{"mismatched units", NewFilter(Blur(Px(2))), NewFilter(Blur(Em(1))), 0.6,
+			"blur(1em)"},
+		{"start", NewFilter(Saturate(1)), NewFilter(Saturate(2)), 0, "saturate(1)"},
+		{"end", NewFilter(Saturate(1)), NewFilter(Saturate(2)), 1, "saturate(2)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.from.Interpolate(tt.to, tt.progress).String(); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransformValidate(t *testing.T) {
+	valid := []TransformValue{
+		NewTransform(),
+		NewTransform(Translate(Px(1), Px(2)), Scale(2), ScaleXY(1, 2), Rotate(Degrees(3)), Skew(Degrees(1), Degrees(2)), Matrix(1, 0, 0, 1, 0, 0)),
+		{{Name: "translate", Args: []FunctionArg{{Value: 1, Unit: "px"}}}},
+	}
+	for _, value := range valid {
+		if err := value.Validate(); err != nil {
+			t.Errorf("Validate(%s) = %v", value, err)
+		}
+	}
+
+	invalid := []TransformValue{
+		{{Name: "warp", Args: []FunctionArg{{Value: 1}}}},
+		{{Name: "rotate"}},
+		{{Name: "translate", Args: []FunctionArg{{Value: 1}, {Value: 2}, {Value: 3}}}},
+		{{Name: "matrix", Args: []FunctionArg{{Value: 1}, {Value: 0}}}},
+	}
+	for _, value := range invalid {
+		if value.Validate() == nil {
+			t.Errorf("Validate(%s) = nil, want an error", value)
+		}
+	}
+
+	if err := (AngleValue{Value: 1, Unit: "grad"}).Validate(); err == nil {
+		t.Error("Validate() = nil for an unknown angle unit")
+	}
+}
+
+func TestFilterValidate(t *testing.T) {
+	valid := NewFilter(Brightness(0), HueRotate(Degrees(-90)), DropShadow(Px(-2), Px(-2), Px(4), ColorBlack))
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Validate(%s) = %v", valid, err)
+	}
+	for _, function := range []FilterFunction{Brightness(-1), Contrast(-0.5), Grayscale(-1), Saturate(-2)} {
+		if NewFilter(function).Validate() == nil {
+			t.Errorf("Validate(%s) = nil, want an error", function)
+		}
+	}
+}
+
+func TestTransformAndFilterOptions(t *testing.T) {
+	s := New(
+		Transform(TranslateY(Px(-4)), Scale(1.05)),
+		Filter(),
+		BackdropFilters(Blur(Px(20)), Saturate(1.8)),
+	)
+	want := Property{
+		"transform":       "translateY(-4px) scale(1.05)",
+		"filter":          "none",
+		"backdrop-filter": "blur(20px) saturate(1.8)",
+	}
+	for property, value := range want {
+		if s.Base[property] != value {
+			t.Errorf("%s = %q, want %q", property, s.Base[property], value)
+		}
+	}
+}
+
+func TestClipPath(t *testing.T) {
+	tests := []struct {
+		value ClipPathValue
+		want  string
+	}{
+		{ClipPathNone, "none"},
+		{ClipInset(Px(10)), "inset(10.00px)"},
+		{ClipInset(Px(10), Percent(5)), "inset(10.00px 5.00%)"},
+		{ClipCircle(Percent(50), Percent(25), Percent(75)), "circle(50.00% at 25.00% 75.00%)"},
+		{ClipEllipse(Px(10), Px(20), Percent(50), Percent(50)), "ellipse(10.00px 20.00px at 50.00% 50.00%)"},
+		{ClipPolygon(Point{Percent(50), Percent(0)}, Point{Percent(100), Percent(100)}, Point{Percent(0), Percent(100)}),
+			"polygon(50.00% 0.00%, 100.00% 100.00%, 0.00% 100.00%)"},
+	}
+	for _, tt := range tests {
+		if got := tt.value.String(); got != tt.want {
+			t.Errorf("got %q, want %q", got, tt.want)
+		}
+		if got := New(ClipPath(tt.value)).Base["clip-path"]; got != tt.want {
+			t.Errorf("clip-path = %q, want %q", got, tt.want)
+		}
+	}
+}
@@ -1,5 +1,3 @@
-//go:build js && wasm
-
 // Package style provides type-safe CSS styling for Vortex components.
 // This file contains the core interfaces and types for the style package.
 //
@@ -143,13 +141,28 @@ const (
 type LengthValue struct {
 	Value float64
 	Unit  LengthUnit
+
+	token string // Theme token referenced instead of the value (see theme.go)
 }
 
 func (l LengthValue) String() string {
+	if l.token != "" {
+		return TokenVar(l.token)
+	}
 	return fmt.Sprintf("%.2f%s", l.Value, l.Unit)
 }
 
+// isSet reports whether the length holds a value or a token
+// The zero LengthValue has no unit and is no length (Validate rejects it), a length of 0 has one: Px(0)
+func (l LengthValue) isSet() bool {
+	return l.Unit != "" || l.token != ""
+}
+
 func (l LengthValue) Validate() error {
+	if l.token != "" {
+		return nil
+	}
+
 	var validUnits = []LengthUnit{
 		UnitPx, UnitPt, UnitPc, UnitIn, UnitMm, UnitCm,
 		UnitEm, UnitRem, UnitEx, UnitCh,
@@ -280,7 +293,15 @@ var (
 	ColorLightSkyBlue       = ColorValue{Value: "light-sky-blue"}
 )
 
+// varPattern matches a reference to a CSS custom property, such as a theme token
+var varPattern = regexp.MustCompile(`^var\(--[A-Za-z0-9_-]+\)$`)
+
 func validateColorString(color string) error {
+	// Custom properties (var(--vtx-primary))
+	if varPattern.MatchString(color) {
+		return nil
+	}
+
 	// Hex colors (#fff, #ffffff)
 	hexPattern := regexp.MustCompile(`^#[0-9a-fA-F]{3}([0-9a-fA-F]{3})?$`)
 	if hexPattern.MatchString(color) {
@@ -377,6 +398,11 @@ func validateLengthString(value string) error {
 		return nil
 	}
 
+	// Custom properties (var(--vtx-space-md))
+	if varPattern.MatchString(value) {
+		return nil
+	}
+
 	// Length pattern : number + unit
 	lengthPattern := regexp.MustCompile(`^\d+(\.\d+)?(px|pt|pc|in|mm|cm|em|rem|ex|ch|vw|vh|vmin|vmax|%)$`)
 	if lengthPattern.MatchString(value) {
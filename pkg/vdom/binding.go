@@ -0,0 +1,150 @@
+package vdom
+
+import (
+	"sort"
+
+	"github.com/AureClai/vortex/pkg/reactive"
+	"github.com/AureClai/vortex/pkg/style"
+)
+
+// Names of the bound parts of a node which are not props (see VNode.Bindings)
+// Prop names never start with '#'
+const (
+	TextPart  = "#text"
+	StylePart = "#style"
+)
+
+// Binding ties the text, a prop or the style of a node to reactive values (see the reactive package)
+//
+// The renderer reads the binding in a reactive effect: whenever the signals it reads change,
+// the DOM node is patched alone, without re-rendering the component nor diffing its tree.
+// Outside the browser (ssr, diff) the binding is simply read once, as a plain value.
+//
+// Usage examples :
+//
+//	count := reactive.NewSignal(0)
+//
+//	counter := vdom.NewComponentBase("div")
+//	counter.AddChildren(vdom.NewBoundText(vdom.Bind(count.Get)))
+//	counter.SetProp("aria-valuenow", vdom.Bind(count.Get))
+//	counter.BindStyle(func() *style.Style {
+//	    if count.Get() > 10 {
+//	        return warningStyle
+//	    }
+//	    return normalStyle
+//	})
+//
+//	count.Set(11) // Patches the text, the attribute and the class of the div, nothing else
+//
+// A binding created in Render is a new one on every render, and is bound again each time:
+// create the bindings once, with the component, when it re-renders often.
+type Binding struct {
+	read func() interface{}
+}
+
+// Bind creates a binding reading its value with read, such as the Get of a signal
+func Bind[T any](read func() T) *Binding {
+	return &Binding{read: func() interface{} { return read() }}
+}
+
+// Read returns the value, subscribing the running reactive effect to what it depends on
+func (b *Binding) Read() interface{} {
+	return b.read()
+}
+
+// Value returns the current value without subscribing to it
+func (b *Binding) Value() interface{} {
+	var value interface{}
+	reactive.Untrack(func() { value = b.read() })
+	return value
+}
+
+// String returns the current value as written in an attribute
+func (b *Binding) String() string {
+	return FormatProp(b.Value())
+}
+
+// ResolveProp returns the current value of a prop, reading it if it is bound
+func ResolveProp(value interface{}) interface{} {
+	if binding, ok := value.(*Binding); ok {
+		return binding.Value()
+	}
+	return value
+}
+
+// NewBoundText creates a text node whose content follows the binding
+func NewBoundText(binding *Binding) *VNode {
+	return &VNode{
+		Type:        VNodeText,
+		Text:        FormatProp(binding.Value()),
+		TextBinding: binding,
+	}
+}
+
+// BindStyle applies the style returned by read, following its changes
+func (c *ComponentBase) BindStyle(read func() *style.Style) *ComponentBase {
+	binding := Bind(read)
+	c.vNode.StyleBinding = binding
+	c.vNode.AppliedStyle, _ = binding.Value().(*style.Style)
+	return c
+}
+
+// HasBindings reports whether a part of the node is bound
+func (v *VNode) HasBindings() bool {
+	if v.TextBinding != nil || v.StyleBinding != nil {
+		return true
+	}
+	for _, value := range v.Props {
+		if _, ok := value.(*Binding); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Bindings returns the bound parts of the node by name: TextPart, StylePart or the name of a prop
+func (v *VNode) Bindings() map[string]*Binding {
+	bindings := make(map[string]*Binding)
+	if v.TextBinding != nil {
+		bindings[TextPart] = v.TextBinding
+	}
+	if v.StyleBinding != nil {
+		bindings[StylePart] = v.StyleBinding
+	}
+	for name, value := range v.Props {
+		if binding, ok := value.(*Binding); ok {
+			bindings[name] = binding
+		}
+	}
+	return bindings
+}
+
+// BindingChanges compares the bindings followed by the parts of a node with its new bindings
+// It returns the parts to stop following and the parts to start following, sorted by name.
+// A part bound to the same binding as before keeps its effect: it is in neither.
+func BindingChanges(followed, bindings map[string]*Binding) (stopped, started []string) {
+	for name, binding := range followed {
+		if bindings[name] != binding {
+			stopped = append(stopped, name)
+		}
+	}
+	for name, binding := range bindings {
+		if followed[name] != binding {
+			started = append(started, name)
+		}
+	}
+	sort.Strings(stopped)
+	sort.Strings(started)
+	return stopped, started
+}
+
+// ApplyBinding writes the value of a bound part to the node, so the next diff compares against it
+// The text goes to Text and the style to AppliedStyle; a bound prop stays the binding in Props.
+func (v *VNode) ApplyBinding(part string, value interface{}) {
+	switch part {
+	case TextPart:
+		v.Text = FormatProp(value)
+	case StylePart:
+		v.AppliedStyle, _ = value.(*style.Style)
+	}
+}
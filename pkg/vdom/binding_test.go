@@ -0,0 +1,52 @@
+package vdom
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/AureClai/vortex/pkg/style"
+)
+
+func TestBindingChanges(t *testing.T) {
+	text := Bind(func() string { return "a" })
+	title := Bind(func() string { return "b" })
+	swapped := Bind(func() string { return "c" })
+
+	tests := []struct {
+		name             string
+		followed         map[string]*Binding
+		bindings         map[string]*Binding
+		stopped, started []string
+	}{
+		{"first bind", nil, map[string]*Binding{TextPart: text, "title": title}, nil, []string{TextPart, "title"}},
+		{"kept", map[string]*Binding{TextPart: text, "title": title}, map[string]*Binding{TextPart: text, "title": title}, nil, nil},
+		{"swapped", map[string]*Binding{TextPart: text, "title": title}, map[string]*Binding{TextPart: text, "title": swapped},
+			[]string{"title"}, []string{"title"}},
+		{"removed", map[string]*Binding{TextPart: text, "title": title}, map[string]*Binding{}, []string{TextPart, "title"}, nil},
+	}
+	for _, tt := range tests {
+		stopped, started := BindingChanges(tt.followed, tt.bindings)
+		if !reflect.DeepEqual(stopped, tt.stopped) || !reflect.DeepEqual(started, tt.started) {
+			t.Errorf("%s: stopped %v, started %v, want %v and %v", tt.name, stopped, started, tt.stopped, tt.started)
+		}
+	}
+}
+
+func TestApplyBinding(t *testing.T) {
+	title := Bind(func() string { return "b" })
+	card := style.New(style.Opacity(0.5))
+	node := &VNode{Type: VNodeElement, Tag: "p", Props: map[string]interface{}{"title": title}}
+
+	node.ApplyBinding(TextPart, 42)
+	node.ApplyBinding(StylePart, card)
+	node.ApplyBinding("title", "ignored")
+	if node.Text != "42" || node.AppliedStyle != card || node.Props["title"] != title {
+		t.Errorf("text %q, style %v, title %v, want the text and the style written, the prop still bound",
+			node.Text, node.AppliedStyle, node.Props["title"])
+	}
+
+	node.ApplyBinding(StylePart, nil)
+	if node.AppliedStyle != nil {
+		t.Errorf("style %v, want no style", node.AppliedStyle)
+	}
+}
@@ -1,9 +1,8 @@
-//go:build js && wasm
-
 package vdom
 
 import (
-	"syscall/js"
+	"maps"
+	"slices"
 
 	"github.com/AureClai/vortex/pkg/style"
 )
@@ -20,15 +19,21 @@ func NewComponentBase(tag string) ComponentBase {
 			Type:          VNodeElement,
 			Tag:           tag,
 			Props:         make(map[string]interface{}),
-			EventHandlers: make(map[string]func(event js.Value)),
+			EventHandlers: make(map[string]func(event Event)),
 			Children:      make([]*VNode, 0),
 		},
 	}
 }
 
-// Render return simply the internal vNode
+// Render returns a copy of the internal vNode
+// Every render hands a new node to the diff, so the props, children and style changed since
+// the previous render are compared with the node rendered then, instead of with themselves
 func (c *ComponentBase) Render() *VNode {
-	return c.vNode
+	node := *c.vNode
+	node.Props = maps.Clone(c.vNode.Props)
+	node.EventHandlers = maps.Clone(c.vNode.EventHandlers)
+	node.Children = slices.Clone(c.vNode.Children)
+	return &node
 }
 
 // --- Common methodes "Fluent" ---
@@ -43,17 +48,44 @@ func (c *ComponentBase) SetID(id string) *ComponentBase {
 	return c
 }
 
+// SetProp sets a prop of the element
+// How it is written to the DOM depends on its name and value (see PropKindOf)
+func (c *ComponentBase) SetProp(name string, value interface{}) *ComponentBase {
+	c.vNode.Props[name] = value
+	return c
+}
+
+// SetValue sets the value of a form element, kept in sync with the DOM
+func (c *ComponentBase) SetValue(value string) *ComponentBase {
+	return c.SetProp("value", value)
+}
+
+// SetChecked sets the checked state of a checkbox or a radio button
+func (c *ComponentBase) SetChecked(checked bool) *ComponentBase {
+	return c.SetProp("checked", checked)
+}
+
+// SetDisabled adds or removes the disabled attribute
+func (c *ComponentBase) SetDisabled(disabled bool) *ComponentBase {
+	return c.SetProp("disabled", disabled)
+}
+
 func (c *ComponentBase) SetKey(key string) *ComponentBase {
 	c.vNode.Key = key
 	return c
 }
 
+// Key returns the key set with SetKey
+func (c *ComponentBase) Key() string {
+	return c.vNode.Key
+}
+
 func (c *ComponentBase) Style(s *style.Style) *ComponentBase {
 	c.vNode.AppliedStyle = s
 	return c
 }
 
-func (c *ComponentBase) On(event string, handler func(event js.Value)) *ComponentBase {
+func (c *ComponentBase) On(event string, handler func(event Event)) *ComponentBase {
 	c.vNode.EventHandlers[event] = handler
 	return c
 }
@@ -63,8 +95,10 @@ func (c *ComponentBase) SetText(text string) *ComponentBase {
 	return c
 }
 
+// AddChild appends the child as a component node
+// The child is rendered by the diff, and can later be re-rendered alone
 func (c *ComponentBase) AddChild(child Component) *ComponentBase {
-	c.vNode.Children = append(c.vNode.Children, child.Render())
+	c.vNode.Children = append(c.vNode.Children, NewComponentVNode(child, nil))
 	return c
 }
 
@@ -0,0 +1,74 @@
+package vdom
+
+import "reflect"
+
+// PropsReceiver is implemented by the components receiving the props of their node
+// SetProps is called before each render of the component with the props of the latest node
+type PropsReceiver interface {
+	SetProps(props map[string]interface{})
+}
+
+// Keyed is implemented by the components carrying a key (see ComponentBase.SetKey)
+type Keyed interface {
+	Key() string
+}
+
+// stateful is implemented by the components embedding StatefulComponentBase
+type stateful interface {
+	isStateful()
+}
+
+// NewComponentVNode creates a node holding a component instance and its props
+// The component is not rendered here: the diff renders it when the node is mounted or patched,
+// and keeps the subtree it rendered in Rendered so the component can later be re-rendered alone
+func NewComponentVNode(c Component, props map[string]interface{}) *VNode {
+	node := &VNode{
+		Type:      VNodeComponent,
+		Props:     props,
+		Component: c,
+	}
+	if keyed, ok := c.(Keyed); ok {
+		node.Key = keyed.Key()
+	}
+	return node
+}
+
+// RenderComponent hands the props to the component and renders it
+// The subtree is stored in Rendered and returned
+func (v *VNode) RenderComponent() *VNode {
+	if receiver, ok := v.Component.(PropsReceiver); ok {
+		receiver.SetProps(v.Props)
+	}
+	v.Rendered = v.Component.Render()
+	return v.Rendered
+}
+
+// Host returns the node holding the DOM reference of v
+// Component nodes have no DOM of their own, they resolve to the node they rendered
+func (v *VNode) Host() *VNode {
+	for v != nil && v.Type == VNodeComponent {
+		v = v.Rendered
+	}
+	return v
+}
+
+// SameComponent reports whether two component nodes hold instances of the same component type
+func SameComponent(a, b *VNode) bool {
+	return reflect.TypeOf(a.Component) == reflect.TypeOf(b.Component)
+}
+
+// SameInstance reports whether two components are the same instance
+// Components held by value are copies: they are never the same instance
+func SameInstance(a, b Component) bool {
+	if reflect.ValueOf(a).Kind() != reflect.Pointer {
+		return false
+	}
+	return a == b
+}
+
+// IsStateful reports whether the component embeds a StatefulComponentBase
+// Stateful instances are kept across the renders of their parent so their state is not lost
+func IsStateful(c Component) bool {
+	_, ok := c.(stateful)
+	return ok
+}
@@ -0,0 +1,11 @@
+//go:build js && wasm
+
+package vdom
+
+import "syscall/js"
+
+// DOMElement is the reference to the real DOM node bound to a VNode
+type DOMElement = js.Value
+
+// Event is the browser event received by the event handlers
+type Event = js.Value
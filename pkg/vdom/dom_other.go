@@ -0,0 +1,11 @@
+//go:build !(js && wasm)
+
+package vdom
+
+// DOMElement is the reference to the real DOM node bound to a VNode
+// Outside the browser there is no DOM, the field is left empty
+type DOMElement = interface{}
+
+// Event is the event received by the event handlers
+// Outside the browser no event is ever dispatched
+type Event = interface{}
@@ -0,0 +1,25 @@
+package vdom
+
+// Fragment markers
+// A fragment has no element of its own: its children are inserted directly in the parent,
+// between two comment nodes delimiting it. The markers keep a place in the DOM for an empty
+// fragment and let the children be appended, moved or removed together.
+const (
+	FragmentStartMarker = "["
+	FragmentEndMarker   = "]"
+)
+
+// NewFragment creates a fragment node holding the children
+// A component can return it to render several root nodes without a wrapping element:
+//
+//	func (r *Row) Render() *vdom.VNode {
+//	    return vdom.NewFragment(cellA.Render(), cellB.Render())
+//	}
+//
+// Like any node, a fragment can be keyed by setting its Key.
+func NewFragment(children ...*VNode) *VNode {
+	return &VNode{
+		Type:     VNodeFragment,
+		Children: children,
+	}
+}
@@ -0,0 +1,28 @@
+package vdom
+
+// Lifecycle hooks
+// A component opts in by implementing any of these interfaces, the renderer calls them:
+//   - OnMount once the DOM of the component has been inserted in the document
+//   - OnUpdate after each patch of the component subtree
+//   - OnUnmount right before the DOM of the component is removed
+//
+// Only stateful components keep the same instance across the renders of their parent.
+// The other ones are replaced by the instance the parent renders: the previous instance receives
+// OnUnmount and the new one OnMount, so every instance receiving OnMount receives OnUnmount.
+// Components holding resources (timers, subscriptions) should still embed StatefulComponentBase,
+// so they are not restarted on every render of their parent.
+
+// Mounter is implemented by the components reacting to their insertion in the DOM
+type Mounter interface {
+	OnMount()
+}
+
+// Updater is implemented by the components reacting to the patch of their subtree
+type Updater interface {
+	OnUpdate()
+}
+
+// Unmounter is implemented by the components cleaning up before their removal from the DOM
+type Unmounter interface {
+	OnUnmount()
+}
@@ -0,0 +1,22 @@
+package vdom
+
+// PortalMarker is the data of the comment holding the place of a portal in its parent
+const PortalMarker = "portal"
+
+// NewPortal creates a portal node rendering the children into another DOM target
+// The target is the element with the given ID, or document.body when the ID is empty.
+// Modals, tooltips and toasts use it to escape the overflow of their parents:
+//
+//	func (m *Modal) Render() *vdom.VNode {
+//	    return vdom.NewPortal("", overlay.Render())
+//	}
+//
+// The portal itself stays in the tree of its owner: its children are diffed and receive
+// their events as any other node, and they are removed from the target with the portal.
+func NewPortal(target string, children ...*VNode) *VNode {
+	return &VNode{
+		Type:     VNodePortal,
+		Target:   target,
+		Children: children,
+	}
+}
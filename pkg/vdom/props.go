@@ -0,0 +1,104 @@
+package vdom
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// PropKind tells how a prop of an element is written to the DOM
+type PropKind int
+
+const (
+	PropAttribute PropKind = iota // Written with setAttribute, as text
+	PropProperty                  // Written as a DOM property (value, checked, ...), always in sync with the DOM
+	PropBoolean                   // Boolean attribute: present when true, removed when false
+	PropStyle                     // Inline style text
+	PropClass                     // Class list, merged with the class of the applied style
+)
+
+// domProperties are the props reflecting a live state of the element
+// They must be written as DOM properties: the attribute only holds the initial value
+var domProperties = map[string]bool{
+	"value":         true,
+	"checked":       true,
+	"selected":      true,
+	"muted":         true,
+	"indeterminate": true,
+}
+
+// booleanAttributes are the HTML attributes whose presence alone means true
+var booleanAttributes = map[string]bool{
+	"allowfullscreen": true,
+	"async":           true,
+	"autofocus":       true,
+	"autoplay":        true,
+	"controls":        true,
+	"default":         true,
+	"defer":           true,
+	"disabled":        true,
+	"formnovalidate":  true,
+	"hidden":          true,
+	"inert":           true,
+	"loop":            true,
+	"multiple":        true,
+	"novalidate":      true,
+	"open":            true,
+	"playsinline":     true,
+	"readonly":        true,
+	"required":        true,
+	"reversed":        true,
+}
+
+// PropKindOf returns how the prop must be written to the DOM
+// Only the HTML boolean attributes are written by presence. The other attributes holding a bool,
+// such as aria-expanded, draggable or contenteditable, are written as "true" or "false"
+func PropKindOf(name string, value interface{}) PropKind {
+	switch {
+	case name == "style":
+		return PropStyle
+	case name == "class":
+		return PropClass
+	case domProperties[name]:
+		return PropProperty
+	case booleanAttributes[name]:
+		return PropBoolean
+	}
+	return PropAttribute
+}
+
+// PropBool returns the truth value of a boolean prop
+// Strings are true unless "false": `disabled: ""` and `disabled: "disabled"` work as in HTML
+func PropBool(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case string:
+		return v != "false"
+	}
+	return true
+}
+
+// FormatProp returns the textual value of a prop, as written in an attribute
+func FormatProp(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case fmt.Stringer:
+		return v.String()
+	}
+	return fmt.Sprintf("%v", value)
+}
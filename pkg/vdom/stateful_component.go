@@ -1,5 +1,3 @@
-//go:build js && wasm
-
 package vdom
 
 // StatefulComponent is a base struct for the components that need to manage their own state
@@ -11,6 +9,11 @@ type StatefulComponentBase[T any] struct {
 }
 
 // NewStatefulComponent creates a new stateful component
+// reRender is called after every state change, usually to schedule an update of the component:
+//
+//	app.StatefulComponentBase = vdom.NewStatefulComponent("div", AppState{}, func() {
+//	    r.ScheduleUpdate(app)
+//	})
 func NewStatefulComponent[T any](tag string, initialState T, reRender func()) StatefulComponentBase[T] {
 	return StatefulComponentBase[T]{
 		ComponentBase: NewComponentBase(tag),
@@ -19,6 +22,9 @@ func NewStatefulComponent[T any](tag string, initialState T, reRender func()) St
 	}
 }
 
+// isStateful marks the components keeping their instance across parent renders
+func (c *StatefulComponentBase[T]) isStateful() {}
+
 // State return the state of the component
 func (c *StatefulComponentBase[T]) State() T {
 	return c.state
@@ -1,10 +1,6 @@
-//go:build js && wasm
-
 package vdom
 
 import (
-	"syscall/js"
-
 	"github.com/AureClai/vortex/pkg/style"
 )
 
@@ -13,19 +9,30 @@ type VNodeType int
 const (
 	VNodeElement VNodeType = iota
 	VNodeText
+	VNodeComponent // A component instance, rendered lazily by the diff (see component_node.go)
+	VNodeFragment  // A list of children inserted directly in the parent (see fragment.go)
+	VNodePortal    // Children rendered in another DOM target (see portal.go)
 )
 
 // VNode represents a virtual node in the DOM
+// It does not depend on syscall/js so the trees can be built and diffed outside the browser
 type VNode struct {
 	Type          VNodeType
-	Tag           string                          // HTML tag name
-	Text          string                          // Text content
-	Props         map[string]interface{}          // Attributes and properties
-	Children      []*VNode                        // Child nodes
-	EventHandlers map[string]func(event js.Value) // Event handlers
-	Key           string                          // Key for list items
-	Element       js.Value                        // Stck la référence à l'élément DOM
-	AppliedStyle  *style.Style                    // the style to apply to this node
+	Tag           string                       // HTML tag name
+	Text          string                       // Text content
+	Props         map[string]interface{}       // Attributes and properties, *Binding when bound (props of the component for VNodeComponent)
+	Children      []*VNode                     // Child nodes
+	EventHandlers map[string]func(event Event) // Event handlers
+	Key           string                       // Key for list items
+	Element       DOMElement                   // Stck la référence à l'élément DOM (opening marker for VNodeFragment, placeholder for VNodePortal)
+	EndElement    DOMElement                   // Closing marker of a VNodeFragment
+	AppliedStyle  *style.Style                 // the style to apply to this node
+	StyleClass    string                       // Class of AppliedStyle on the element, set by the backend when applied
+	Component     Component                    // Component instance of a VNodeComponent
+	Rendered      *VNode                       // Subtree rendered last by the component of a VNodeComponent
+	Target        string                       // ID of the DOM target of a VNodePortal, empty for document.body
+	TextBinding   *Binding                     // Binding of the Text of a VNodeText (see binding.go)
+	StyleBinding  *Binding                     // Binding of the AppliedStyle, read as a *style.Style
 }
 
 type Component interface {